@@ -0,0 +1,65 @@
+package sawmill
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// BadKey is the key of arguments that are not part of a key/value pair,
+// matching log/slog
+const BadKey = "!BADKEY"
+
+// ErrMalformedArgs is reported by loggers with strict arguments for calls
+// with a key that has no value or an argument that is not a key
+var ErrMalformedArgs = errors.New("sawmill: malformed key/value arguments")
+
+// malformedArgError describes the malformed argument at index i
+func malformedArgError(args []interface{}, i int) error {
+	if key, ok := args[i].(string); ok {
+		return fmt.Errorf("%w: key %q has no value", ErrMalformedArgs, key)
+	}
+	return fmt.Errorf("%w: argument %d (%T) is not a string key", ErrMalformedArgs, i, args[i])
+}
+
+// reportArgsError passes a malformed-arguments error to the logger's error
+// handler without counting it as a failed write
+func (l *logger) reportArgsError(err error, record *Record) {
+	l.mu.RLock()
+	onError := l.onError
+	l.mu.RUnlock()
+
+	notifyError(onError, err, record)
+}
+
+// argsLogger applies the argument rules of loggers to records built directly
+var argsLogger = &logger{}
+
+// Add adds key/value arguments to the record with the same rules as the
+// logging methods, including !BADKEY for malformed arguments
+func (r *Record) Add(args ...interface{}) *Record {
+	argsLogger.processArgs(r, args...)
+	return r
+}
+
+// setSlogValue stores slog attributes, groups and errors carried by value at
+// dotPath and reports whether value was one of them
+func (f *FlatAttributes) setSlogValue(dotPath string, value interface{}) bool {
+	switch v := value.(type) {
+	case slog.Attr:
+		if err, ok := asError(v.Value.Any()); ok {
+			f.SetError(dotPath+"."+v.Key, err)
+		} else {
+			f.SetAttr([]string{dotPath}, v)
+		}
+	case slog.Value:
+		f.SetAttr(nil, slog.Attr{Key: dotPath, Value: v})
+	default:
+		if err, ok := asError(value); ok {
+			f.SetError(dotPath, err)
+			return true
+		}
+		return false
+	}
+	return true
+}
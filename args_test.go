@@ -0,0 +1,136 @@
+package sawmill
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestMalformedArgsBadKey(t *testing.T) {
+	tests := []struct {
+		name string
+		args []interface{}
+		want []string
+	}{
+		{"missing value", []interface{}{"user", "alice", "dangling"}, []string{`"user":"alice"`, `"!BADKEY":"dangling"`}},
+		{"non-string key", []interface{}{42}, []string{`"!BADKEY":42`}},
+		{"non-string key before pair", []interface{}{true, "user", "alice"}, []string{`"!BADKEY":true`, `"user":"alice"`}},
+		{"several malformed", []interface{}{42, "user", "alice", "dangling"}, []string{`"!BADKEY":[42,"dangling"]`, `"user":"alice"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			New(NewJSONHandler(WithWriter(buf))).Info("Malformed", tt.args...)
+
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("Expected %s in output: %s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestMalformedArgsInGroup(t *testing.T) {
+	buf := &bytes.Buffer{}
+	New(NewJSONHandler(WithWriter(buf))).WithGroup("req").Info("Grouped", 7)
+
+	if !strings.Contains(buf.String(), `"req.!BADKEY":7`) {
+		t.Errorf("Expected !BADKEY inside the group: %s", buf.String())
+	}
+}
+
+func TestSlogAttrAndGroupArgs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf)))
+
+	logger.Info("Mixed",
+		slog.Group("http", slog.String("method", "GET"), slog.Int("status", 200)),
+		"user", "alice",
+		slog.Any("retry", true),
+		"request", slog.GroupValue(slog.String("id", "r-1")),
+	)
+
+	output := buf.String()
+	for _, want := range []string{`"http.method":"GET"`, `"http.status":200`, `"user":"alice"`, `"retry":true`, `"request.id":"r-1"`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %s in output: %s", want, output)
+		}
+	}
+	if strings.Contains(output, BadKey) {
+		t.Errorf("Expected no !BADKEY for well-formed arguments: %s", output)
+	}
+}
+
+func TestStrictArgs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var reported []error
+	logger := New(NewJSONHandler(WithWriter(buf))).WithErrorHandler(func(err error, record *Record) {
+		reported = append(reported, err)
+	})
+
+	logger.Info("Lenient", "dangling")
+	if len(reported) != 0 {
+		t.Errorf("Expected no reports without strict mode, got %v", reported)
+	}
+
+	strict := logger.WithStrictArgs(true)
+	strict.Info("Strict", "user", "alice")
+	strict.Info("Strict", 42, "dangling")
+	strict.As(NewJSONFormatter()).Info("As", "dangling")
+
+	if len(reported) != 2 {
+		t.Fatalf("Expected one report per malformed call, got %v", reported)
+	}
+	if !errors.Is(reported[0], ErrMalformedArgs) || !strings.Contains(reported[0].Error(), "argument 0 (int) is not a string key") {
+		t.Errorf("Unexpected error: %v", reported[0])
+	}
+	if !strings.Contains(reported[1].Error(), `key "dangling" has no value`) {
+		t.Errorf("Unexpected error: %v", reported[1])
+	}
+	if count := strings.Count(buf.String(), `"!BADKEY"`); count != 3 {
+		t.Errorf("Expected malformed records to still be written, got %d: %s", count, buf.String())
+	}
+	if !strings.Contains(buf.String(), `"!BADKEY":[42,"dangling"]`) {
+		t.Errorf("Expected every malformed argument to be kept: %s", buf.String())
+	}
+}
+
+func TestRecordAddAndWith(t *testing.T) {
+	record := NewRecord(LevelInfo, "Built")
+	record.Add("user", "alice", slog.Int("attempt", 2), "dangling")
+	record.With([]string{"http"}, slog.GroupValue(slog.String("method", "GET")))
+	record.WithDot("error", errors.New("boom"))
+
+	expected := map[string]interface{}{
+		"user":          "alice",
+		"attempt":       int64(2),
+		BadKey:          "dangling",
+		"http.method":   "GET",
+		"error.message": "boom",
+	}
+	for key, want := range expected {
+		if got, _ := record.Attributes.GetByDotNotation(key); got != want {
+			t.Errorf("Expected %s=%v, got %v", key, want, got)
+		}
+	}
+}
+
+func TestWithAttrsExpandsErrors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	attrLogger := New(NewJSONHandler(WithWriter(buf))).(*logger).WithAttrs([]slog.Attr{
+		slog.Any("cause", errors.New("boom")),
+		slog.Group("svc", slog.String("name", "billing")),
+	})
+
+	attrLogger.Info("Attrs")
+	output := buf.String()
+	for _, want := range []string{`"cause.message":"boom"`, `"svc.name":"billing"`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %s in output: %s", want, output)
+		}
+	}
+}
@@ -2,8 +2,10 @@ package sawmill
 
 import (
 	"bytes"
+	"log/slog"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestAsMethod(t *testing.T) {
@@ -121,3 +123,41 @@ func TestAsMethodLevelMethods(t *testing.T) {
 		}
 	}
 }
+
+func TestAsMethodHandlerAttrs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewTextHandler(WithDestination(NewWriterDestination(buf)))
+	logger := New(handler.WithAttrs([]slog.Attr{slog.String("service", "api")}))
+
+	logger.As(NewJSONFormatter()).Info("JSON formatted message")
+
+	if !strings.Contains(buf.String(), `"service":"api"`) {
+		t.Errorf("Expected handler attributes on the As() record: %s", buf.String())
+	}
+}
+
+func TestAsMethodOutputFormat(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewTextHandler(WithDestination(NewWriterDestination(buf)))).WithCallback(func(record *Record) *Record {
+		record.Time = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		return record
+	})
+
+	jsonFormatter := NewJSONFormatter()
+	jsonFormatter.IncludeSource = false
+	textFormatter := NewTextFormatter()
+	textFormatter.IncludeSource = false
+	kvFormatter := NewKeyValueFormatter()
+	kvFormatter.IncludeSource = false
+
+	logger.As(jsonFormatter).Info("Formatted", "key", "value")
+	logger.As(textFormatter).Info("Formatted")
+	logger.As(kvFormatter).Info("Formatted")
+
+	want := `{"timestamp":"2024-01-02T03:04:05Z","message":"Formatted","level":"INFO","attributes":{"key":"value"}}` + "\n" +
+		"2024-01-02 03:04:05 [INFO] Formatted\n" +
+		"timestamp=2024-01-02 03:04:05 level=INFO message=Formatted\n"
+	if buf.String() != want {
+		t.Errorf("Expected As() output without an output ID:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
@@ -0,0 +1,272 @@
+package sawmill
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what an AsyncHandler does when its queue is full
+type OverflowPolicy int
+
+const (
+	OverflowBlock          OverflowPolicy = iota // Wait for space in the queue
+	OverflowDropNewest                           // Drop the incoming record
+	OverflowDropOldest                           // Drop the oldest queued record to make room
+	OverflowDropBelowLevel                       // Drop incoming records below DropLevel, block for the rest
+)
+
+// AsyncOptions configures an AsyncHandler
+type AsyncOptions struct {
+	QueueSize int            // Capacity of the record queue
+	Workers   int            // Number of goroutines writing to the wrapped handler
+	Overflow  OverflowPolicy // Behavior when the queue is full
+	DropLevel Level          // Minimum level kept under OverflowDropBelowLevel
+	OnError   ErrorHandler   // Receives errors from the wrapped handler; nil uses DefaultErrorHandler
+}
+
+// DefaultAsyncOptions returns options for a single worker with a 1024 record queue that blocks when full
+func DefaultAsyncOptions() *AsyncOptions {
+	return &AsyncOptions{
+		QueueSize: 1024,
+		Workers:   1,
+		Overflow:  OverflowBlock,
+		DropLevel: LevelWarn,
+	}
+}
+
+// AsyncStats reports AsyncHandler queue metrics
+type AsyncStats struct {
+	Enqueued      uint64 // Records accepted into the queue
+	Processed     uint64 // Records passed to the wrapped handler
+	Dropped       uint64 // Records discarded by the overflow policy
+	QueueDepth    int    // Records currently waiting in the queue
+	QueueCapacity int    // Maximum number of queued records
+}
+
+// AsyncHandler wraps a handler and writes records on background workers.
+// Handle copies the record before queueing it, so callers may return the
+// original to the pool as soon as Handle returns. Flush waits until the queue
+// is drained; Close drains the queue, stops the workers and closes the wrapped
+// handler. Records handled after Close are written synchronously.
+type AsyncHandler struct {
+	handler Handler
+	opts    *AsyncOptions
+	state   *asyncState
+}
+
+// asyncState holds the queue and workers shared by an AsyncHandler and its WithAttrs/WithGroup copies
+type asyncState struct {
+	queue   chan asyncItem
+	workers sync.WaitGroup
+
+	closeMu   sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+	closeErr  error
+
+	pendingMu sync.Mutex
+	idle      *sync.Cond
+	pending   int
+
+	enqueued  atomic.Uint64
+	processed atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+// asyncItem is a queued record with the handler and context it was logged with
+type asyncItem struct {
+	ctx     context.Context
+	handler Handler
+	record  *Record
+}
+
+// NewAsyncHandler wraps handler and starts its workers; nil options use DefaultAsyncOptions
+func NewAsyncHandler(handler Handler, opts *AsyncOptions) *AsyncHandler {
+	if opts == nil {
+		opts = DefaultAsyncOptions()
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultAsyncOptions().QueueSize
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+
+	state := &asyncState{
+		queue: make(chan asyncItem, opts.QueueSize),
+	}
+	state.idle = sync.NewCond(&state.pendingMu)
+
+	h := &AsyncHandler{
+		handler: handler,
+		opts:    opts,
+		state:   state,
+	}
+
+	state.workers.Add(opts.Workers)
+	for i := 0; i < opts.Workers; i++ {
+		go h.work()
+	}
+
+	return h
+}
+
+// Unwrap returns the wrapped handler
+func (h *AsyncHandler) Unwrap() Handler {
+	return h.handler
+}
+
+// RetainsRecords implements RecordRetainer; queued records are already clones
+func (h *AsyncHandler) RetainsRecords() bool {
+	return false
+}
+
+// Stats returns the current queue metrics
+func (h *AsyncHandler) Stats() AsyncStats {
+	return AsyncStats{
+		Enqueued:      h.state.enqueued.Load(),
+		Processed:     h.state.processed.Load(),
+		Dropped:       h.state.dropped.Load(),
+		QueueDepth:    len(h.state.queue),
+		QueueCapacity: cap(h.state.queue),
+	}
+}
+
+func (h *AsyncHandler) Handle(ctx context.Context, record *Record) error {
+	s := h.state
+
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+
+	if s.closed {
+		return h.handler.Handle(ctx, record)
+	}
+
+	// Valuers are resolved on the caller's goroutine, at log time
+	record.ResolveValuers()
+	item := asyncItem{
+		ctx:     context.WithoutCancel(ctx),
+		handler: h.handler,
+		record:  record.Clone(),
+	}
+
+	s.addPending(1)
+	if !h.enqueue(item) {
+		s.addPending(-1)
+		s.dropped.Add(1)
+		return nil
+	}
+	s.enqueued.Add(1)
+	return nil
+}
+
+// enqueue applies the overflow policy and reports whether the item was queued
+func (h *AsyncHandler) enqueue(item asyncItem) bool {
+	s := h.state
+
+	select {
+	case s.queue <- item:
+		return true
+	default:
+	}
+
+	switch h.opts.Overflow {
+	case OverflowDropNewest:
+		return false
+	case OverflowDropOldest:
+		for {
+			select {
+			case s.queue <- item:
+				return true
+			default:
+			}
+			select {
+			case <-s.queue:
+				s.dropped.Add(1)
+				s.addPending(-1)
+			default:
+			}
+		}
+	case OverflowDropBelowLevel:
+		if item.record.Level < h.opts.DropLevel {
+			return false
+		}
+	}
+
+	s.queue <- item
+	return true
+}
+
+// work writes queued records to their handlers until the queue is closed
+func (h *AsyncHandler) work() {
+	defer h.state.workers.Done()
+
+	for item := range h.state.queue {
+		if err := item.handler.Handle(item.ctx, item.record); err != nil {
+			reportError(h.opts.OnError, err, item.record)
+		}
+		h.state.processed.Add(1)
+		h.state.addPending(-1)
+	}
+}
+
+// addPending adjusts the number of queued or in-flight records and wakes
+// waiters when it reaches zero
+func (s *asyncState) addPending(delta int) {
+	s.pendingMu.Lock()
+	s.pending += delta
+	if s.pending == 0 {
+		s.idle.Broadcast()
+	}
+	s.pendingMu.Unlock()
+}
+
+// drain waits until every queued record has been written
+func (s *asyncState) drain() {
+	s.pendingMu.Lock()
+	for s.pending > 0 {
+		s.idle.Wait()
+	}
+	s.pendingMu.Unlock()
+}
+
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) Handler {
+	return &AsyncHandler{handler: h.handler.WithAttrs(attrs), opts: h.opts, state: h.state}
+}
+
+func (h *AsyncHandler) WithGroup(name string) Handler {
+	return &AsyncHandler{handler: h.handler.WithGroup(name), opts: h.opts, state: h.state}
+}
+
+func (h *AsyncHandler) Enabled(ctx context.Context, level Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// NeedsSource reports whether the wrapped handler needs source information
+func (h *AsyncHandler) NeedsSource() bool {
+	return handlerNeedsSource(h.handler)
+}
+
+// Flush waits for the queue to drain and flushes the wrapped handler
+func (h *AsyncHandler) Flush() error {
+	h.state.drain()
+	return flushHandler(h.handler)
+}
+
+// Close drains the queue, stops the workers and closes the wrapped handler
+func (h *AsyncHandler) Close() error {
+	s := h.state
+	s.closeOnce.Do(func() {
+		s.closeMu.Lock()
+		s.closed = true
+		s.closeMu.Unlock()
+
+		close(s.queue)
+		s.workers.Wait()
+		s.closeErr = errors.Join(flushHandler(h.handler), closeHandler(h.handler))
+	})
+	return s.closeErr
+}
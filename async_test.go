@@ -0,0 +1,148 @@
+package sawmill
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// gatedHandler blocks in Handle until released and signals each call
+type gatedHandler struct {
+	*BaseHandler
+	started chan struct{}
+	release chan struct{}
+}
+
+func newGatedHandler(buf *syncBuffer) *gatedHandler {
+	return &gatedHandler{
+		BaseHandler: NewBaseHandler(NewTextFormatter(), NewWriterBuffer(buf), LevelTrace),
+		started:     make(chan struct{}, 16),
+		release:     make(chan struct{}),
+	}
+}
+
+func (h *gatedHandler) Handle(ctx context.Context, record *Record) error {
+	h.started <- struct{}{}
+	<-h.release
+	return h.BaseHandler.Handle(ctx, record)
+}
+
+func TestAsyncHandlerWritesDetachedRecords(t *testing.T) {
+	buf := &syncBuffer{}
+	handler := NewAsyncHandler(NewTextHandler(WithWriter(buf), WithAttributeFormat("flat")), &AsyncOptions{
+		QueueSize: 64,
+		Workers:   4,
+	})
+	logger := New(handler)
+
+	for i := 0; i < 50; i++ {
+		logger.Info("Queued", "seq", i)
+	}
+
+	if err := logger.Flush(); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+
+	output := buf.String()
+	for i := 0; i < 50; i++ {
+		if !strings.Contains(output, fmt.Sprintf("seq=%d\n", i)) {
+			t.Fatalf("Expected record %d to keep its attributes after pooling: %s", i, output)
+		}
+	}
+
+	stats := handler.Stats()
+	if stats.Enqueued != 50 || stats.Processed != 50 || stats.Dropped != 0 || stats.QueueCapacity != 64 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestAsyncHandlerOverflowPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   OverflowPolicy
+		expected []string
+		missing  string
+	}{
+		{"DropNewest", OverflowDropNewest, []string{"first", "second"}, "third"},
+		{"DropOldest", OverflowDropOldest, []string{"first", "third"}, "second"},
+		{"DropBelowLevel", OverflowDropBelowLevel, []string{"first", "second"}, "third"},
+	}
+
+	for _, test := range tests {
+		buf := &syncBuffer{}
+		gated := newGatedHandler(buf)
+		handler := NewAsyncHandler(gated, &AsyncOptions{
+			QueueSize: 1,
+			Workers:   1,
+			Overflow:  test.policy,
+			DropLevel: LevelWarn,
+		})
+		logger := New(handler)
+
+		logger.Warn("first")
+		<-gated.started
+		logger.Warn("second")
+		logger.Info("third")
+
+		if depth := handler.Stats().QueueDepth; depth != 1 {
+			t.Errorf("%s: expected queue depth 1, got %d", test.name, depth)
+		}
+
+		close(gated.release)
+		if err := handler.Close(); err != nil {
+			t.Fatalf("%s: Close returned error: %v", test.name, err)
+		}
+
+		output := buf.String()
+		for _, msg := range test.expected {
+			if !strings.Contains(output, msg) {
+				t.Errorf("%s: expected %q in output: %s", test.name, msg, output)
+			}
+		}
+		if strings.Contains(output, test.missing) {
+			t.Errorf("%s: expected %q to be dropped: %s", test.name, test.missing, output)
+		}
+		if dropped := handler.Stats().Dropped; dropped != 1 {
+			t.Errorf("%s: expected 1 dropped record, got %d", test.name, dropped)
+		}
+	}
+}
+
+func TestAsyncHandlerCloseDrainsQueue(t *testing.T) {
+	buf := &syncBuffer{}
+	handler := NewAsyncHandler(NewJSONHandler(WithWriter(buf)), nil)
+	logger := New(handler).WithDot("component", "queue")
+
+	for i := 0; i < 10; i++ {
+		logger.Info("Before shutdown")
+	}
+	if err := logger.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+	if count := strings.Count(buf.String(), "Before shutdown"); count != 10 {
+		t.Errorf("Expected every queued record to be written, got %d", count)
+	}
+
+	logger.Info("After shutdown")
+	if !strings.Contains(buf.String(), "After shutdown") {
+		t.Error("Expected records after Close to be written synchronously")
+	}
+}
+
+func TestAsyncHandlerResolvesLazyOnCaller(t *testing.T) {
+	buf := &syncBuffer{}
+	handler := NewAsyncHandler(NewJSONHandler(WithWriter(buf)), nil)
+	logger := New(handler)
+
+	x := 1
+	logger.Info("Lazy", "x", Lazy(func() any { return x }))
+	x++
+
+	if err := handler.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"x":1`) {
+		t.Errorf("Expected the value at log time: %s", buf.String())
+	}
+}
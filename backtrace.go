@@ -0,0 +1,207 @@
+package sawmill
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// BacktraceOptions configures a BacktraceHandler
+type BacktraceOptions struct {
+	Size         int   // Number of recent records kept in the ring buffer
+	CaptureLevel Level // Lowest level kept in the ring buffer
+	TriggerLevel Level // Records at or above this level replay the ring buffer
+}
+
+// DefaultBacktraceOptions returns options that keep the last 100 debug records and replay them on errors
+func DefaultBacktraceOptions() *BacktraceOptions {
+	return &BacktraceOptions{
+		Size:         100,
+		CaptureLevel: LevelDebug,
+		TriggerLevel: LevelError,
+	}
+}
+
+// BacktraceStats reports BacktraceHandler buffer metrics
+type BacktraceStats struct {
+	Captured  uint64 // Records kept in the ring buffer
+	Replayed  uint64 // Buffered records written ahead of a trigger record
+	Discarded uint64 // Buffered records overwritten before a trigger arrived
+	Buffered  int    // Records currently in the ring buffer
+}
+
+// BacktraceHandler wraps a handler and keeps the most recent records that the
+// handler's level would drop in a ring buffer, unformatted. When a record at
+// or above TriggerLevel arrives, the buffered records are written ahead of it
+// and all of them share one OutputID.
+type BacktraceHandler struct {
+	handler Handler
+	opts    *BacktraceOptions
+	state   *backtraceState
+}
+
+// backtraceState is the ring buffer shared by a BacktraceHandler and its WithAttrs/WithGroup copies
+type backtraceState struct {
+	mu    sync.Mutex
+	ring  []backtraceItem
+	start int
+	count int
+
+	captured  atomic.Uint64
+	replayed  atomic.Uint64
+	discarded atomic.Uint64
+}
+
+// backtraceItem is a buffered record with the handler it was logged to
+type backtraceItem struct {
+	handler Handler
+	record  *Record
+}
+
+// NewBacktraceHandler wraps handler with a backtrace buffer; nil options use DefaultBacktraceOptions
+func NewBacktraceHandler(handler Handler, opts *BacktraceOptions) *BacktraceHandler {
+	if opts == nil {
+		opts = DefaultBacktraceOptions()
+	}
+	if opts.Size <= 0 {
+		opts.Size = DefaultBacktraceOptions().Size
+	}
+
+	return &BacktraceHandler{
+		handler: handler,
+		opts:    opts,
+		state:   &backtraceState{ring: make([]backtraceItem, opts.Size)},
+	}
+}
+
+// Unwrap returns the wrapped handler
+func (h *BacktraceHandler) Unwrap() Handler {
+	return h.handler
+}
+
+// Stats returns the buffer metrics
+func (h *BacktraceHandler) Stats() BacktraceStats {
+	h.state.mu.Lock()
+	buffered := h.state.count
+	h.state.mu.Unlock()
+
+	return BacktraceStats{
+		Captured:  h.state.captured.Load(),
+		Replayed:  h.state.replayed.Load(),
+		Discarded: h.state.discarded.Load(),
+		Buffered:  buffered,
+	}
+}
+
+func (h *BacktraceHandler) Handle(ctx context.Context, record *Record) error {
+	if !record.levelOverride && !h.handler.Enabled(ctx, record.Level) {
+		if record.Level >= h.opts.CaptureLevel {
+			h.capture(record)
+		}
+		return nil
+	}
+
+	if record.Level < h.opts.TriggerLevel {
+		return h.handler.Handle(ctx, record)
+	}
+
+	items := h.state.take()
+	if len(items) == 0 {
+		return h.handler.Handle(ctx, record)
+	}
+
+	outputID := record.OutputID
+	if outputID == "" {
+		outputID = generateOutputID()
+	}
+
+	var errs []error
+	for _, item := range items {
+		item.record.OutputID = outputID
+		item.record.correlated = true
+		item.record.levelOverride = true
+		errs = append(errs, item.handler.Handle(ctx, item.record))
+	}
+	h.state.replayed.Add(uint64(len(items)))
+
+	record.OutputID = outputID
+	record.correlated = true
+	errs = append(errs, h.handler.Handle(ctx, record))
+	return errors.Join(errs...)
+}
+
+// capture adds a copy of the record to the ring buffer, overwriting the oldest when full
+func (h *BacktraceHandler) capture(record *Record) {
+	// Valuers are resolved at log time so replays show the values of that moment
+	record.ResolveValuers()
+	item := backtraceItem{handler: h.handler, record: record.Clone()}
+	s := h.state
+
+	s.mu.Lock()
+	if s.count == len(s.ring) {
+		s.ring[s.start] = item
+		s.start = (s.start + 1) % len(s.ring)
+		s.discarded.Add(1)
+	} else {
+		s.ring[(s.start+s.count)%len(s.ring)] = item
+		s.count++
+	}
+	s.mu.Unlock()
+
+	s.captured.Add(1)
+}
+
+// take removes and returns the buffered records, oldest first
+func (s *backtraceState) take() []backtraceItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.count == 0 {
+		return nil
+	}
+
+	items := make([]backtraceItem, s.count)
+	for i := range items {
+		index := (s.start + i) % len(s.ring)
+		items[i] = s.ring[index]
+		s.ring[index] = backtraceItem{}
+	}
+	s.start = 0
+	s.count = 0
+	return items
+}
+
+func (h *BacktraceHandler) WithAttrs(attrs []slog.Attr) Handler {
+	return &BacktraceHandler{handler: h.handler.WithAttrs(attrs), opts: h.opts, state: h.state}
+}
+
+func (h *BacktraceHandler) WithGroup(name string) Handler {
+	return &BacktraceHandler{handler: h.handler.WithGroup(name), opts: h.opts, state: h.state}
+}
+
+// Enabled reports true for levels that are written or captured
+func (h *BacktraceHandler) Enabled(ctx context.Context, level Level) bool {
+	return level >= h.opts.CaptureLevel || h.handler.Enabled(ctx, level)
+}
+
+// NeedsSource reports whether the wrapped handler needs source information
+func (h *BacktraceHandler) NeedsSource() bool {
+	return handlerNeedsSource(h.handler)
+}
+
+// RetainsRecords implements RecordRetainer; buffered records are already clones
+func (h *BacktraceHandler) RetainsRecords() bool {
+	return false
+}
+
+// Flush flushes the wrapped handler; buffered records are kept for the next trigger
+func (h *BacktraceHandler) Flush() error {
+	return flushHandler(h.handler)
+}
+
+// Close closes the wrapped handler
+func (h *BacktraceHandler) Close() error {
+	return closeHandler(h.handler)
+}
@@ -0,0 +1,101 @@
+package sawmill
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBacktraceHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewBacktraceHandler(NewJSONHandler(WithWriter(buf), WithLevel(LevelInfo)), &BacktraceOptions{
+		Size:         3,
+		CaptureLevel: LevelDebug,
+		TriggerLevel: LevelError,
+	})
+	logger := New(handler)
+
+	logger.Trace("Not captured")
+	for i := 0; i < 5; i++ {
+		logger.Debug("Step", "seq", i)
+	}
+	logger.Info("Visible")
+
+	if strings.Contains(buf.String(), "Step") {
+		t.Fatalf("Expected debug records to stay buffered: %s", buf.String())
+	}
+
+	logger.Error("Failed")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 lines, got %d: %s", len(lines), buf.String())
+	}
+
+	var entries []map[string]interface{}
+	for _, line := range lines {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid JSON %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if entries[0]["message"] != "Visible" || entries[0]["output_id"] != nil {
+		t.Errorf("Expected the info record without an output ID first: %v", entries[0])
+	}
+
+	outputID := entries[4]["output_id"]
+	if entries[4]["message"] != "Failed" || outputID == nil || outputID == "" {
+		t.Fatalf("Expected the error record last with an output ID: %v", entries[4])
+	}
+	for i, entry := range entries[1:4] {
+		attrs := entry["attributes"].(map[string]interface{})
+		if entry["level"] != "DEBUG" || attrs["seq"] != float64(i+2) || entry["output_id"] != outputID {
+			t.Errorf("Unexpected replayed record %d: %v", i, entry)
+		}
+	}
+
+	stats := handler.Stats()
+	if stats.Captured != 5 || stats.Replayed != 3 || stats.Discarded != 2 || stats.Buffered != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	buf.Reset()
+	logger.Error("Second failure")
+	if strings.Contains(buf.String(), "output_id") {
+		t.Errorf("Expected no backtrace with an empty buffer: %s", buf.String())
+	}
+}
+
+func TestBacktraceHandlerWithAttrs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewBacktraceHandler(NewTextHandler(WithWriter(buf)), nil)
+
+	New(handler.WithAttrs(nil)).Debug("Loading config")
+	New(handler).Error("Startup failed")
+
+	out := buf.String()
+	if strings.Index(out, "Loading config") > strings.Index(out, "Startup failed") || !strings.Contains(out, "Loading config") {
+		t.Errorf("Expected copies to share the buffer: %s", out)
+	}
+	if !handler.Enabled(context.Background(), LevelDebug) || handler.Enabled(context.Background(), LevelTrace) {
+		t.Error("Expected Enabled to include the capture level")
+	}
+}
+
+func TestBacktraceHandlerResolvesLazyAtLogTime(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewBacktraceHandler(NewJSONHandler(WithWriter(buf), WithLevel(LevelInfo)), nil))
+
+	x := 1
+	logger.Debug("Step", "x", Lazy(func() any { return x }))
+	x++
+	logger.Error("Failed")
+
+	if !strings.Contains(buf.String(), `"message":"Step"`) || !strings.Contains(buf.String(), `"x":1`) {
+		t.Errorf("Expected the replayed record to show the value at log time: %s", buf.String())
+	}
+}
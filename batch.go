@@ -0,0 +1,329 @@
+package sawmill
+
+import (
+	"context"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchSink receives groups of formatted records, such as a bulk API client.
+// Each entry is one formatted record including its trailing newline; the
+// slice is not reused after WriteBatch returns.
+type BatchSink interface {
+	WriteBatch(ctx context.Context, batch [][]byte) error
+}
+
+// BatchSinkFunc adapts a function to the BatchSink interface
+type BatchSinkFunc func(ctx context.Context, batch [][]byte) error
+
+// WriteBatch calls f(ctx, batch)
+func (f BatchSinkFunc) WriteBatch(ctx context.Context, batch [][]byte) error {
+	return f(ctx, batch)
+}
+
+// BatchOptions configures a BatchHandler
+type BatchOptions struct {
+	Formatter      Formatter                       // Record formatter; nil uses a JSON formatter built from the handler options
+	MaxRecords     int                             // Send when this many records are pending
+	MaxBytes       int                             // Send when pending records reach this many bytes
+	MaxLatency     time.Duration                   // Send pending records at most this long after the first one arrives
+	QueueSize      int                             // Batches waiting for the sender before new batches wait or are dropped
+	DropWhenFull   bool                            // Drop new batches instead of waiting when the queue is full
+	MaxRetries     int                             // Retries after a failed WriteBatch before the batch is dead-lettered
+	InitialBackoff time.Duration                   // Delay before the first retry, doubled on each attempt
+	MaxBackoff     time.Duration                   // Upper bound for the retry delay
+	DeadLetter     func(batch [][]byte, err error) // Receives batches that exhausted their retries
+}
+
+// DefaultBatchOptions returns options for batches of up to 500 records or 1 MiB, sent at least every second
+func DefaultBatchOptions() *BatchOptions {
+	return &BatchOptions{
+		MaxRecords:     500,
+		MaxBytes:       1 << 20,
+		MaxLatency:     time.Second,
+		QueueSize:      4,
+		MaxRetries:     3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+	}
+}
+
+// BatchStats reports BatchHandler delivery metrics
+type BatchStats struct {
+	Batches      uint64 // Batches delivered to the sink
+	Records      uint64 // Records delivered to the sink
+	Retries      uint64 // Failed WriteBatch calls that were retried
+	DeadLettered uint64 // Records in batches that exhausted their retries
+	Dropped      uint64 // Records in batches dropped because the queue was full
+}
+
+// BatchHandler formats records and delivers them to a BatchSink in groups.
+// A batch is sent when MaxRecords or MaxBytes is reached or MaxLatency
+// passes. Batches are sent in order on a background goroutine; when
+// QueueSize batches are waiting, new batches wait for the sender or are
+// dropped with DropWhenFull. Flush and Close send whatever is pending and
+// wait for delivery.
+type BatchHandler struct {
+	*BaseHandler
+	batcher *batchBuffer
+}
+
+// NewBatchHandler creates a handler that batches records for sink; nil options use DefaultBatchOptions
+func NewBatchHandler(sink BatchSink, batchOpts *BatchOptions, options ...HandlerOption) *BatchHandler {
+	opts := NewHandlerOptions(options...)
+	if batchOpts == nil {
+		batchOpts = DefaultBatchOptions()
+	}
+
+	formatter := batchOpts.Formatter
+	if formatter == nil {
+		formatter = createJSONFormatter(opts)
+	}
+
+	batcher := newBatchBuffer(sink, batchOpts)
+	return &BatchHandler{
+		BaseHandler: newBaseHandlerWithBuffer(formatter, batcher, opts),
+		batcher:     batcher,
+	}
+}
+
+// Stats returns the delivery metrics
+func (h *BatchHandler) Stats() BatchStats {
+	return BatchStats{
+		Batches:      h.batcher.batches.Load(),
+		Records:      h.batcher.records.Load(),
+		Retries:      h.batcher.retries.Load(),
+		DeadLettered: h.batcher.deadLettered.Load(),
+		Dropped:      h.batcher.dropped.Load(),
+	}
+}
+
+// batchBuffer is a Buffer that groups writes into batches for a BatchSink
+type batchBuffer struct {
+	sink BatchSink
+	opts BatchOptions
+
+	mu           sync.Mutex
+	pending      [][]byte
+	pendingBytes int
+	timer        *time.Timer
+	closed       bool
+
+	queueMu  sync.Mutex // Held while queueing, so batches are queued in the order they were taken
+	requests chan batchRequest
+	sender   sync.WaitGroup
+
+	batches      atomic.Uint64
+	records      atomic.Uint64
+	retries      atomic.Uint64
+	deadLettered atomic.Uint64
+	dropped      atomic.Uint64
+}
+
+// batchRequest is a batch queued for the sender; done receives the result when set
+type batchRequest struct {
+	batch [][]byte
+	done  chan error
+}
+
+// newBatchBuffer creates a batch buffer and starts its sender
+func newBatchBuffer(sink BatchSink, opts *BatchOptions) *batchBuffer {
+	defaults := DefaultBatchOptions()
+	b := &batchBuffer{
+		sink: sink,
+		opts: *opts,
+	}
+	if b.opts.QueueSize <= 0 {
+		b.opts.QueueSize = defaults.QueueSize
+	}
+	b.requests = make(chan batchRequest, b.opts.QueueSize)
+	if b.opts.MaxRecords <= 0 {
+		b.opts.MaxRecords = defaults.MaxRecords
+	}
+	if b.opts.MaxBytes <= 0 {
+		b.opts.MaxBytes = defaults.MaxBytes
+	}
+	if b.opts.MaxLatency <= 0 {
+		b.opts.MaxLatency = defaults.MaxLatency
+	}
+	if b.opts.InitialBackoff <= 0 {
+		b.opts.InitialBackoff = defaults.InitialBackoff
+	}
+	if b.opts.MaxBackoff < b.opts.InitialBackoff {
+		b.opts.MaxBackoff = b.opts.InitialBackoff
+	}
+
+	b.sender.Add(1)
+	go b.run()
+	return b
+}
+
+func (b *batchBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+
+	if b.closed {
+		b.mu.Unlock()
+		return 0, os.ErrClosed
+	}
+
+	entry := make([]byte, len(p))
+	copy(entry, p)
+	b.pending = append(b.pending, entry)
+	b.pendingBytes += len(entry)
+
+	if len(b.pending) >= b.opts.MaxRecords || b.pendingBytes >= b.opts.MaxBytes {
+		b.enqueue(batchRequest{batch: b.take()})
+		return len(p), nil
+	}
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.opts.MaxLatency, b.flushPending)
+	}
+	b.mu.Unlock()
+	return len(p), nil
+}
+
+// flushPending queues the pending records when the latency timer fires
+func (b *batchBuffer) flushPending() {
+	b.mu.Lock()
+
+	if b.closed || len(b.pending) == 0 {
+		b.mu.Unlock()
+		return
+	}
+	b.enqueue(batchRequest{batch: b.take()})
+}
+
+// take removes and returns the pending records; callers must hold the lock
+func (b *batchBuffer) take() [][]byte {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	batch := b.pending
+	b.pending = nil
+	b.pendingBytes = 0
+	return batch
+}
+
+// enqueue queues request for the sender. Callers must hold b.mu, which is
+// released before waiting for room in the queue; requests without a done
+// channel are dropped instead when the queue is full and DropWhenFull is set.
+func (b *batchBuffer) enqueue(request batchRequest) {
+	b.queueMu.Lock()
+	b.mu.Unlock()
+	defer b.queueMu.Unlock()
+
+	if request.done == nil && b.opts.DropWhenFull {
+		select {
+		case b.requests <- request:
+		default:
+			b.dropped.Add(uint64(len(request.batch)))
+		}
+		return
+	}
+	b.requests <- request
+}
+
+// Flush sends the pending records and waits until every queued batch is delivered
+func (b *batchBuffer) Flush() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	done := make(chan error, 1)
+	b.enqueue(batchRequest{batch: b.take(), done: done})
+
+	return <-done
+}
+
+// Close sends the pending records, waits for delivery and stops the sender
+func (b *batchBuffer) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		b.sender.Wait()
+		return nil
+	}
+	b.closed = true
+	done := make(chan error, 1)
+	request := batchRequest{batch: b.take(), done: done}
+
+	b.queueMu.Lock()
+	b.mu.Unlock()
+	b.requests <- request
+	close(b.requests)
+	b.queueMu.Unlock()
+
+	err := <-done
+	b.sender.Wait()
+	return err
+}
+
+func (b *batchBuffer) Size() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return int64(b.pendingBytes)
+}
+
+func (b *batchBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.take()
+}
+
+// run delivers queued batches in order
+func (b *batchBuffer) run() {
+	defer b.sender.Done()
+
+	for request := range b.requests {
+		err := b.send(request.batch)
+		if request.done != nil {
+			request.done <- err
+		} else if err != nil && b.opts.DeadLetter == nil {
+			reportError(nil, err, nil)
+		}
+	}
+}
+
+// send writes a batch to the sink, retrying with exponential backoff and
+// jitter, and dead-letters it once the retries are exhausted
+func (b *batchBuffer) send(batch [][]byte) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	backoff := b.opts.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		err := b.sink.WriteBatch(context.Background(), batch)
+		if err == nil {
+			b.batches.Add(1)
+			b.records.Add(uint64(len(batch)))
+			return nil
+		}
+
+		if attempt >= b.opts.MaxRetries {
+			b.deadLettered.Add(uint64(len(batch)))
+			if b.opts.DeadLetter != nil {
+				b.opts.DeadLetter(batch, err)
+			}
+			return err
+		}
+
+		b.retries.Add(1)
+		time.Sleep(jitter(backoff))
+		backoff = min(backoff*2, b.opts.MaxBackoff)
+	}
+}
+
+// jitter returns a random duration between d/2 and d
+func jitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + rand.N(half)
+}
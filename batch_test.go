@@ -0,0 +1,218 @@
+package sawmill
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink stores delivered batches and fails the first failures calls
+type recordingSink struct {
+	mu       sync.Mutex
+	batches  [][][]byte
+	calls    int
+	failures int
+}
+
+func (s *recordingSink) WriteBatch(ctx context.Context, batch [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.calls <= s.failures {
+		return errTestWrite
+	}
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func (s *recordingSink) sizes() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sizes := make([]int, len(s.batches))
+	for i, batch := range s.batches {
+		sizes[i] = len(batch)
+	}
+	return sizes
+}
+
+func TestBatchHandlerCountLimit(t *testing.T) {
+	sink := &recordingSink{}
+	opts := DefaultBatchOptions()
+	opts.MaxRecords = 3
+	opts.MaxLatency = time.Minute
+
+	handler := NewBatchHandler(sink, opts)
+	logger := New(handler)
+
+	for i := 0; i < 7; i++ {
+		logger.Info("Batched", "seq", i)
+	}
+	if err := logger.Flush(); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+
+	if sizes := sink.sizes(); len(sizes) != 3 || sizes[0] != 3 || sizes[1] != 3 || sizes[2] != 1 {
+		t.Errorf("Expected batches of 3, 3 and 1, got %v", sizes)
+	}
+	if !strings.Contains(string(sink.batches[0][0]), `"seq":0`) || !strings.Contains(string(sink.batches[2][0]), `"seq":6`) {
+		t.Errorf("Expected batches in order: %q", sink.batches)
+	}
+
+	stats := handler.Stats()
+	if stats.Batches != 3 || stats.Records != 7 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestBatchHandlerByteLimitAndLatency(t *testing.T) {
+	sink := &recordingSink{}
+	handler := NewBatchHandler(sink, &BatchOptions{
+		MaxRecords: 1000,
+		MaxBytes:   1,
+		MaxLatency: time.Minute,
+	}, WithLevel(LevelDebug))
+	New(handler).Debug("Large enough")
+	handler.Flush()
+
+	if sizes := sink.sizes(); len(sizes) != 1 || sizes[0] != 1 {
+		t.Errorf("Expected byte limit to send immediately, got %v", sizes)
+	}
+
+	sink = &recordingSink{}
+	handler = NewBatchHandler(sink, &BatchOptions{MaxLatency: 10 * time.Millisecond})
+	New(handler).Info("Waiting for timer")
+
+	deadline := time.Now().Add(time.Second)
+	for len(sink.sizes()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected latency timer to send the pending batch")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBatchHandlerRetries(t *testing.T) {
+	sink := &recordingSink{failures: 2}
+	handler := NewBatchHandler(sink, &BatchOptions{
+		MaxRetries:     3,
+		InitialBackoff: time.Millisecond,
+	})
+	New(handler).Info("Eventually delivered")
+
+	if err := handler.Flush(); err != nil {
+		t.Fatalf("Expected retries to succeed, got %v", err)
+	}
+	if stats := handler.Stats(); stats.Retries != 2 || stats.Batches != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestBatchHandlerDeadLetter(t *testing.T) {
+	sink := &recordingSink{failures: 100}
+
+	var deadBatch [][]byte
+	var deadErr error
+	handler := NewBatchHandler(sink, &BatchOptions{
+		MaxRetries:     2,
+		InitialBackoff: time.Millisecond,
+		DeadLetter: func(batch [][]byte, err error) {
+			deadBatch, deadErr = batch, err
+		},
+	})
+	New(handler).Error("Undeliverable")
+
+	if err := handler.Flush(); !errors.Is(err, errTestWrite) {
+		t.Errorf("Expected Flush to return the sink error, got %v", err)
+	}
+	if len(deadBatch) != 1 || !strings.Contains(string(deadBatch[0]), "Undeliverable") || !errors.Is(deadErr, errTestWrite) {
+		t.Errorf("Expected batch to be dead-lettered: %q %v", deadBatch, deadErr)
+	}
+	if stats := handler.Stats(); stats.Retries != 2 || stats.DeadLettered != 1 || sink.calls != 3 {
+		t.Errorf("Unexpected stats: %+v, calls %d", stats, sink.calls)
+	}
+}
+
+func TestBatchHandlerShutdownFlushesPending(t *testing.T) {
+	sink := &recordingSink{}
+	logger := New(NewBatchHandler(BatchSinkFunc(sink.WriteBatch), nil))
+
+	logger.Info("Pending at shutdown")
+	if err := logger.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+	if sizes := sink.sizes(); len(sizes) != 1 {
+		t.Errorf("Expected pending records to be sent on shutdown, got %v", sizes)
+	}
+
+	logger.Info("After shutdown")
+	if sizes := sink.sizes(); len(sizes) != 1 {
+		t.Errorf("Expected no delivery after shutdown, got %v", sizes)
+	}
+}
+
+func TestBatchHandlerDropWhenFull(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	sink := &recordingSink{}
+	var once sync.Once
+	handler := NewBatchHandler(BatchSinkFunc(func(ctx context.Context, batch [][]byte) error {
+		once.Do(func() { close(started) })
+		<-release
+		return sink.WriteBatch(ctx, batch)
+	}), &BatchOptions{MaxRecords: 1, QueueSize: 1, DropWhenFull: true})
+	logger := New(handler)
+
+	logger.Info("Sending")
+	<-started
+	logger.Info("Queued")
+
+	returned := make(chan struct{})
+	go func() {
+		logger.Info("Dropped")
+		logger.Info("Dropped too")
+		close(returned)
+	}()
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("Expected logging not to wait for a stalled sink")
+	}
+
+	close(release)
+	if err := handler.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if stats := handler.Stats(); stats.Dropped != 2 || stats.Records != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestBatchHandlerCloseLeavesNothingPending(t *testing.T) {
+	sink := &recordingSink{}
+	handler := NewBatchHandler(sink, &BatchOptions{MaxRecords: 1000}, WithErrorHandler(func(error, *Record) {}))
+	logger := New(handler)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Info("Concurrent")
+			}
+		}()
+	}
+	if err := handler.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	wg.Wait()
+
+	if size := handler.GetBuffer().Size(); size != 0 {
+		t.Errorf("Expected no records left pending after Close, got %d bytes", size)
+	}
+}
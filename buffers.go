@@ -72,6 +72,7 @@ type FileBuffer struct {
 	size     int64
 	maxSize  int64
 	autoSync bool
+	closed   bool
 }
 
 // NewFileBuffer creates a new file buffer
@@ -100,6 +101,10 @@ func (b *FileBuffer) Write(p []byte) (int, error) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
+	if b.closed {
+		return 0, os.ErrClosed
+	}
+
 	if b.maxSize > 0 && b.size+int64(len(p)) > b.maxSize {
 		b.file.Truncate(0)
 		b.file.Seek(0, 0)
@@ -125,6 +130,9 @@ func (b *FileBuffer) Write(p []byte) (int, error) {
 func (b *FileBuffer) Flush() error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	if b.closed {
+		return nil
+	}
 	err := b.writer.Flush()
 	if err != nil {
 		return err
@@ -132,10 +140,16 @@ func (b *FileBuffer) Flush() error {
 	return b.file.Sync()
 }
 
+// Close flushes and closes the file; closing an already closed buffer is a no-op
 func (b *FileBuffer) Close() error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+
 	if err := b.writer.Flush(); err != nil {
 		b.file.Close()
 		return err
@@ -276,6 +290,13 @@ func (b *WriterBuffer) Flush() error {
 }
 
 func (b *WriterBuffer) Close() error {
+	if err := b.Flush(); err != nil {
+		return err
+	}
+	// Standard streams stay open for the rest of the process
+	if b.writer == os.Stdout || b.writer == os.Stderr {
+		return nil
+	}
 	if closer, ok := b.writer.(io.Closer); ok {
 		return closer.Close()
 	}
@@ -0,0 +1,83 @@
+package sawmill
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const maxCallerDepth = 32 // Maximum number of frames searched for the caller
+
+var (
+	helperFuncs sync.Map // Function name -> struct{} for functions marked with MarkHelper
+	helperPCs   sync.Map // Program counter of MarkHelper calls -> struct{}
+	skippedPCs  sync.Map // Program counter -> bool, whether the frame is skipped
+)
+
+// MarkHelper marks the calling function as a logging helper, like
+// testing.T.Helper. Records logged from a helper report the source of the
+// helper's caller. Calling MarkHelper more than once is cheap.
+//
+// Example usage:
+//
+//	func logRequest(r *http.Request) {
+//	    sawmill.MarkHelper()
+//	    logger.Info("Request", "path", r.URL.Path)
+//	}
+func MarkHelper() {
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	if _, marked := helperPCs.LoadOrStore(pcs[0], struct{}{}); marked {
+		return
+	}
+
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	if _, exists := helperFuncs.LoadOrStore(frame.Function, struct{}{}); !exists {
+		skippedPCs.Clear()
+	}
+}
+
+// isSkippedPC reports whether the frame at pc belongs to the package itself,
+// the standard library's log package or a function marked with MarkHelper
+func isSkippedPC(pc uintptr) bool {
+	if skipped, ok := skippedPCs.Load(pc); ok {
+		return skipped.(bool)
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	_, helper := helperFuncs.Load(frame.Function)
+	skipped := helper || isPackageFrame(frame) || strings.HasPrefix(frame.Function, "log.")
+	skippedPCs.Store(pc, skipped)
+	return skipped
+}
+
+// isPackageFrame reports whether frame is a function of the package, excluding its tests
+func isPackageFrame(frame runtime.Frame) bool {
+	return strings.HasPrefix(frame.Function, sawmillPackage) && !strings.HasSuffix(frame.File, "_test.go")
+}
+
+// callerPCs returns the program counters of the calling goroutine starting
+// at the first frame outside the package and helpers, after skipping skip
+// more frames
+func callerPCs(skip int, pcs []uintptr) []uintptr {
+	// Skip runtime.Callers and callerPCs
+	n := runtime.Callers(2, pcs)
+	pcs = pcs[:n]
+
+	for len(pcs) > 0 && isSkippedPC(pcs[0]) {
+		pcs = pcs[1:]
+	}
+	if skip >= len(pcs) {
+		return nil
+	}
+	return pcs[skip:]
+}
+
+// callerPC returns the program counter reported as the source of a record
+func callerPC(skip int) uintptr {
+	var buf [maxCallerDepth]uintptr
+	if pcs := callerPCs(skip, buf[:]); len(pcs) > 0 {
+		return pcs[0]
+	}
+	return 0
+}
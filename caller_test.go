@@ -0,0 +1,177 @@
+package sawmill
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// assertSource checks the source of the last record in buf against the line after wantLine
+func assertSource(t *testing.T, buf *bytes.Buffer, wantLine int) {
+	t.Helper()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var entry struct {
+		Source struct {
+			Function string `json:"function"`
+			File     string `json:"file"`
+			Line     int    `json:"line"`
+		} `json:"source"`
+	}
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &entry); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", buf.String(), err)
+	}
+	if !strings.HasSuffix(entry.Source.File, "caller_test.go") || entry.Source.Line != wantLine+1 {
+		t.Errorf("Expected source caller_test.go:%d, got %s:%d (%s)", wantLine+1, entry.Source.File, entry.Source.Line, entry.Source.Function)
+	}
+}
+
+func newSourceLogger(buf *bytes.Buffer) Logger {
+	return New(NewJSONHandler(WithWriter(buf), WithSourceInfo(true), WithLevel(LevelTrace)))
+}
+
+func sourceJSONFormatter() *JSONFormatter {
+	formatter := NewJSONFormatter()
+	formatter.IncludeSource = true
+	return formatter
+}
+
+func TestSourceLoggerMethods(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := newSourceLogger(buf)
+	ctx := context.Background()
+	var line int
+
+	_, _, line, _ = runtime.Caller(0)
+	logger.Info("Info")
+	assertSource(t, buf, line)
+
+	_, _, line, _ = runtime.Caller(0)
+	logger.WarnContext(ctx, "WarnContext")
+	assertSource(t, buf, line)
+
+	_, _, line, _ = runtime.Caller(0)
+	logger.Log(ctx, LevelError, "Log")
+	assertSource(t, buf, line)
+
+	_, _, line, _ = runtime.Caller(0)
+	logger.LogAttrs(ctx, LevelInfo, "LogAttrs", String("k", "v"))
+	assertSource(t, buf, line)
+
+	_, _, line, _ = runtime.Caller(0)
+	logger.WithDot("k", "v").Named("child").Mark("Mark")
+	assertSource(t, buf, line)
+}
+
+func TestSourceAsLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := newSourceLogger(buf)
+	var line int
+
+	_, _, line, _ = runtime.Caller(0)
+	logger.As(sourceJSONFormatter()).Debug("As Debug")
+	assertSource(t, buf, line)
+
+	_, _, line, _ = runtime.Caller(0)
+	logger.As(sourceJSONFormatter()).Log(context.Background(), LevelInfo, "As Log")
+	assertSource(t, buf, line)
+}
+
+func TestSourcePackageFunctions(t *testing.T) {
+	buf := &bytes.Buffer{}
+	previous := DefaultLogger
+	DefaultLogger = newSourceLogger(buf)
+	defer func() { DefaultLogger = previous }()
+	var line int
+
+	_, _, line, _ = runtime.Caller(0)
+	Info("Package Info")
+	assertSource(t, buf, line)
+
+	_, _, line, _ = runtime.Caller(0)
+	ErrorContext(context.Background(), "Package ErrorContext")
+	assertSource(t, buf, line)
+
+	_, _, line, _ = runtime.Caller(0)
+	WithDot("k", "v").Trace("Package WithDot")
+	assertSource(t, buf, line)
+}
+
+func TestSourcePanic(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := newSourceLogger(buf)
+
+	var line int
+	func() {
+		defer func() { recover() }()
+		_, _, line, _ = runtime.Caller(0)
+		logger.As(sourceJSONFormatter()).Panic("As Panic")
+	}()
+	assertSource(t, buf, line)
+}
+
+// logViaHelper is a logging helper marked with MarkHelper
+func logViaHelper(logger Logger, msg string) {
+	MarkHelper()
+	logger.Info(msg)
+}
+
+// logViaWrapper is a logging wrapper that relies on WithCallerSkip
+func logViaWrapper(logger Logger, msg string) {
+	logger.Info(msg)
+}
+
+func TestSourceMarkHelper(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := newSourceLogger(buf)
+	var line int
+
+	_, _, line, _ = runtime.Caller(0)
+	logViaHelper(logger, "First")
+	assertSource(t, buf, line)
+
+	_, _, line, _ = runtime.Caller(0)
+	logViaHelper(logger, "Second")
+	assertSource(t, buf, line)
+}
+
+func TestSourceWithCallerSkip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := newSourceLogger(buf)
+	var line int
+
+	_, _, line, _ = runtime.Caller(0)
+	logViaWrapper(logger.WithCallerSkip(1), "Skipped")
+	assertSource(t, buf, line)
+
+	buf.Reset()
+	logViaWrapper(logger, "Not skipped")
+	if !strings.Contains(buf.String(), "logViaWrapper") {
+		t.Errorf("Expected the wrapper as source without a skip: %s", buf.String())
+	}
+}
+
+func TestErrorStackRespectsHelpers(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf))).WithErrorStack(LevelInfo)
+
+	func() {
+		MarkHelper()
+		logger.Info("Failed", Err(errString("boom")))
+	}()
+
+	var entry map[string]interface{}
+	json.Unmarshal(buf.Bytes(), &entry)
+	stack := entry["attributes"].(map[string]interface{})["error.stack"].([]interface{})
+	if top := stack[0].(string); !strings.Contains(top, "TestErrorStackRespectsHelpers ") {
+		t.Errorf("Expected the stack to start at the helper's caller, got %q", top)
+	}
+}
+
+// errString is a minimal error type
+type errString string
+
+func (e errString) Error() string { return string(e) }
@@ -0,0 +1,42 @@
+package sawmill
+
+import "context"
+
+// loggerContextKey is the context key for request-scoped loggers
+type loggerContextKey struct{}
+
+// IntoContext returns a copy of ctx carrying the given logger
+func IntoContext(ctx context.Context, logger Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx, or DefaultLogger if none is present
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerContextKey{}).(Logger); ok && logger != nil {
+			return logger
+		}
+	}
+	return DefaultLogger
+}
+
+// ContextExtractor copies request-scoped data from a context into a record
+type ContextExtractor func(ctx context.Context, record *Record)
+
+// ContextValueExtractor returns an extractor that stores ctx.Value(key) at the given dot path
+//
+// Example usage:
+//
+//	handler := sawmill.NewJSONHandler(
+//	    sawmill.WithContextExtractor(sawmill.ContextValueExtractor(traceIDKey{}, "trace.id")),
+//	)
+func ContextValueExtractor(key interface{}, dotPath string) ContextExtractor {
+	return func(ctx context.Context, record *Record) {
+		if value := ctx.Value(key); value != nil {
+			record.WithDot(dotPath, value)
+		}
+	}
+}
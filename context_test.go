@@ -0,0 +1,105 @@
+package sawmill
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+type testContextKey string
+
+func TestLoggerContextMethods(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var seen context.Context
+	handler := NewTextHandler(WithDestination(NewWriterDestination(buf)), WithLevel(LevelTrace),
+		WithContextExtractor(func(ctx context.Context, record *Record) {
+			seen = ctx
+		}),
+	)
+	logger := New(handler)
+
+	ctx := context.WithValue(context.Background(), testContextKey("request_id"), "req-1")
+
+	tests := []struct {
+		name    string
+		logFunc func(context.Context, string, ...interface{})
+	}{
+		{"TraceContext", logger.TraceContext},
+		{"DebugContext", logger.DebugContext},
+		{"InfoContext", logger.InfoContext},
+		{"WarnContext", logger.WarnContext},
+		{"ErrorContext", logger.ErrorContext},
+		{"MarkContext", logger.MarkContext},
+		{"AsInfoContext", logger.As(NewJSONFormatter()).InfoContext},
+	}
+
+	for _, test := range tests {
+		seen = nil
+		buf.Reset()
+		test.logFunc(ctx, "Context message")
+
+		if buf.Len() == 0 {
+			t.Errorf("%s: expected output", test.name)
+		}
+		if seen != ctx {
+			t.Errorf("%s: expected handler to receive the caller context", test.name)
+		}
+	}
+}
+
+func TestIntoAndFromContext(t *testing.T) {
+	if FromContext(context.Background()) != DefaultLogger {
+		t.Error("Expected DefaultLogger when no logger is stored")
+	}
+
+	buf := &bytes.Buffer{}
+	logger := New(NewTextHandler(WithDestination(NewWriterDestination(buf)))).WithDot("request.id", "req-42")
+	ctx := IntoContext(context.Background(), logger)
+
+	FromContext(ctx).Info("Request scoped message")
+
+	output := buf.String()
+	if !strings.Contains(output, "Request scoped message") || !strings.Contains(output, "req-42") {
+		t.Errorf("Expected request-scoped logger output: %s", output)
+	}
+}
+
+func TestContextValueExtractor(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewJSONHandler(
+		WithDestination(NewWriterDestination(buf)),
+		WithContextExtractor(
+			ContextValueExtractor(testContextKey("trace_id"), "trace.id"),
+			ContextValueExtractor(testContextKey("request_id"), "request.id"),
+		),
+	)
+	logger := New(handler)
+
+	ctx := context.WithValue(context.Background(), testContextKey("trace_id"), "abc123")
+	logger.InfoContext(ctx, "Traced message", "key", "value")
+
+	output := buf.String()
+	if !strings.Contains(output, `"trace.id":"abc123"`) {
+		t.Errorf("Expected trace id extracted from context: %s", output)
+	}
+	if strings.Contains(output, "request.id") {
+		t.Errorf("Expected missing context values to be skipped: %s", output)
+	}
+	if !strings.Contains(output, `"key":"value"`) {
+		t.Errorf("Expected record attributes to be preserved: %s", output)
+	}
+}
+
+func TestRegisterContextExtractor(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewJSONHandler(WithDestination(NewWriterDestination(buf)))
+	handler.RegisterContextExtractor(ContextValueExtractor(testContextKey("user_id"), "user.id"))
+
+	ctx := context.WithValue(context.Background(), testContextKey("user_id"), 7)
+	New(handler.WithGroup("app")).InfoContext(ctx, "Extracted after WithGroup")
+
+	if !strings.Contains(buf.String(), `"user.id":7`) {
+		t.Errorf("Expected extractor to survive WithGroup: %s", buf.String())
+	}
+}
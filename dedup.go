@@ -0,0 +1,224 @@
+package sawmill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DedupOptions configures a DedupHandler
+type DedupOptions struct {
+	Window time.Duration // Repeats within this window after a record is logged are suppressed
+	Keys   []string      // Attribute keys (dot notation) that identify a record; empty means all attributes
+}
+
+// DefaultDedupOptions returns options with a 30 second window over all attributes
+func DefaultDedupOptions() *DedupOptions {
+	return &DedupOptions{
+		Window: 30 * time.Second,
+	}
+}
+
+// DedupHandler wraps a handler and collapses consecutive repeats of the same
+// record. A record is identified by its level, message and the configured
+// attribute keys. Repeats inside the window are suppressed; when the window
+// closes or a different record arrives, a single "last message repeated N
+// times" record is written in their place.
+type DedupHandler struct {
+	handler Handler
+	opts    *DedupOptions
+	state   *dedupState
+}
+
+// dedupState tracks the current run of repeats; it is shared by a DedupHandler
+// and its WithAttrs/WithGroup copies
+type dedupState struct {
+	mu          sync.Mutex
+	fingerprint string
+	windowEnd   time.Time
+	timer       *time.Timer
+	pending     dedupRun
+}
+
+// dedupRun describes the suppressed repeats of the last logged record
+type dedupRun struct {
+	handler       Handler
+	level         Level
+	message       string
+	loggerName    string
+	levelOverride bool
+	count         int
+	firstSeen     time.Time // Time of the logged record that started the run
+	lastSeen      time.Time
+}
+
+// NewDedupHandler wraps handler with duplicate suppression; nil options use DefaultDedupOptions
+func NewDedupHandler(handler Handler, opts *DedupOptions) *DedupHandler {
+	if opts == nil {
+		opts = DefaultDedupOptions()
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultDedupOptions().Window
+	}
+
+	return &DedupHandler{
+		handler: handler,
+		opts:    opts,
+		state:   &dedupState{},
+	}
+}
+
+// Unwrap returns the wrapped handler
+func (h *DedupHandler) Unwrap() Handler {
+	return h.handler
+}
+
+func (h *DedupHandler) Handle(ctx context.Context, record *Record) error {
+	now := record.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	fingerprint := h.fingerprint(record)
+
+	h.state.mu.Lock()
+	if fingerprint == h.state.fingerprint && now.Before(h.state.windowEnd) {
+		h.suppress(now)
+		h.state.mu.Unlock()
+		return nil
+	}
+
+	run := h.state.takeRun()
+	h.state.fingerprint = fingerprint
+	h.state.windowEnd = now.Add(h.opts.Window)
+	h.state.pending = dedupRun{
+		handler:       h.handler,
+		level:         record.Level,
+		message:       record.Message,
+		loggerName:    record.LoggerName,
+		levelOverride: record.levelOverride,
+		firstSeen:     now,
+	}
+	h.state.mu.Unlock()
+
+	return errors.Join(run.emit(ctx), h.handler.Handle(ctx, record))
+}
+
+// suppress counts a repeat and schedules the summary for the end of the window;
+// callers must hold the state lock
+func (h *DedupHandler) suppress(now time.Time) {
+	run := &h.state.pending
+	run.count++
+	run.lastSeen = now
+
+	if h.state.timer == nil {
+		windowEnd := h.state.windowEnd
+		h.state.timer = time.AfterFunc(time.Until(windowEnd), func() {
+			h.closeWindow(windowEnd)
+		})
+	}
+}
+
+// closeWindow writes the summary for a window that ended without a different record
+func (h *DedupHandler) closeWindow(windowEnd time.Time) {
+	h.state.mu.Lock()
+	if !h.state.windowEnd.Equal(windowEnd) {
+		h.state.mu.Unlock()
+		return
+	}
+	run := h.state.takeRun()
+	h.state.fingerprint = ""
+	h.state.mu.Unlock()
+
+	if err := run.emit(context.Background()); err != nil {
+		reportError(nil, err, nil)
+	}
+}
+
+// takeRun returns the pending run and stops its timer; callers must hold the lock
+func (s *dedupState) takeRun() dedupRun {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	run := s.pending
+	s.pending = dedupRun{}
+	return run
+}
+
+// emit writes the summary record for a run with suppressed repeats
+func (r dedupRun) emit(ctx context.Context) error {
+	if r.count == 0 || r.handler == nil {
+		return nil
+	}
+
+	summary := NewRecord(r.level, fmt.Sprintf("last message repeated %d times", r.count))
+	summary.Context = ctx
+	summary.LoggerName = r.loggerName
+	summary.levelOverride = r.levelOverride
+	summary.Attributes.SetByDotNotation("dedup.message", r.message)
+	summary.Attributes.SetByDotNotation("dedup.count", r.count)
+	summary.Attributes.SetByDotNotation("dedup.first_seen", r.firstSeen.Format(time.RFC3339Nano))
+	summary.Attributes.SetByDotNotation("dedup.last_seen", r.lastSeen.Format(time.RFC3339Nano))
+	return r.handler.Handle(ctx, summary)
+}
+
+// fingerprint identifies a record by level, message and the configured attributes
+func (h *DedupHandler) fingerprint(record *Record) string {
+	var b strings.Builder
+	b.WriteString(record.LoggerName)
+	b.WriteByte(0)
+	b.WriteString(levelToString(record.Level))
+	b.WriteByte(0)
+	b.WriteString(record.Message)
+
+	keys := h.opts.Keys
+	if len(keys) == 0 {
+		keys = record.Attributes.Keys()
+		sort.Strings(keys)
+	}
+	for _, key := range keys {
+		value, ok := record.Attributes.GetByDotNotation(key)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, "\x00%s=%v", key, value)
+	}
+	return b.String()
+}
+
+func (h *DedupHandler) WithAttrs(attrs []slog.Attr) Handler {
+	return &DedupHandler{handler: h.handler.WithAttrs(attrs), opts: h.opts, state: h.state}
+}
+
+func (h *DedupHandler) WithGroup(name string) Handler {
+	return &DedupHandler{handler: h.handler.WithGroup(name), opts: h.opts, state: h.state}
+}
+
+func (h *DedupHandler) Enabled(ctx context.Context, level Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// NeedsSource reports whether the wrapped handler needs source information
+func (h *DedupHandler) NeedsSource() bool {
+	return handlerNeedsSource(h.handler)
+}
+
+// Flush writes the summary for any pending repeats and flushes the wrapped handler
+func (h *DedupHandler) Flush() error {
+	h.state.mu.Lock()
+	run := h.state.takeRun()
+	h.state.fingerprint = ""
+	h.state.mu.Unlock()
+
+	return errors.Join(run.emit(context.Background()), flushHandler(h.handler))
+}
+
+// Close closes the wrapped handler
+func (h *DedupHandler) Close() error {
+	return closeHandler(h.handler)
+}
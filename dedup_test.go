@@ -0,0 +1,123 @@
+package sawmill
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes and reads
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestDedupHandlerCollapsesRepeats(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewDedupHandler(NewJSONHandler(WithWriter(buf)), &DedupOptions{Window: time.Minute})
+	logger := New(handler)
+
+	for i := 0; i < 4; i++ {
+		logger.Error("Connection refused", "host", "db-1")
+	}
+	logger.Error("Connection refused", "host", "db-2")
+
+	output := buf.String()
+	if count := strings.Count(output, `"message":"Connection refused"`); count != 2 {
+		t.Errorf("Expected the first record and the different host to be logged, got %d: %s", count, output)
+	}
+	if !strings.Contains(output, `"message":"last message repeated 3 times"`) {
+		t.Errorf("Expected repeat summary: %s", output)
+	}
+	if !strings.Contains(output, `"dedup.count":3`) || !strings.Contains(output, "dedup.first_seen") || !strings.Contains(output, "dedup.last_seen") {
+		t.Errorf("Expected summary attributes: %s", output)
+	}
+	if !strings.Contains(output, `"message":"last message repeated 3 times","level":"ERROR"`) {
+		t.Errorf("Expected summary at the original level: %s", output)
+	}
+
+	summary := strings.Index(output, "last message repeated")
+	second := strings.Index(output, "db-2")
+	if summary < 0 || second < summary {
+		t.Errorf("Expected summary before the different record: %s", output)
+	}
+}
+
+func TestDedupHandlerKeys(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewDedupHandler(NewTextHandler(WithWriter(buf), WithSourceInfo(false)), &DedupOptions{
+		Window: time.Minute,
+		Keys:   []string{"host"},
+	})
+	logger := New(handler)
+
+	logger.Warn("Retrying", "host", "db-1", "attempt", 1)
+	logger.Warn("Retrying", "host", "db-1", "attempt", 2)
+	logger.Flush()
+
+	output := buf.String()
+	if count := strings.Count(output, "] Retrying"); count != 1 {
+		t.Errorf("Expected attributes outside Keys to be ignored, got %d: %s", count, output)
+	}
+	if !strings.Contains(output, "last message repeated 1 times") {
+		t.Errorf("Expected Flush to write the pending summary: %s", output)
+	}
+}
+
+func TestDedupHandlerWindowCloses(t *testing.T) {
+	buf := &syncBuffer{}
+	handler := NewDedupHandler(NewTextHandler(WithWriter(buf), WithSourceInfo(false)), &DedupOptions{Window: 20 * time.Millisecond})
+	logger := New(handler)
+
+	logger.Info("Heartbeat failed")
+	logger.Info("Heartbeat failed")
+	logger.Info("Heartbeat failed")
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "last message repeated 2 times") {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected summary when the window closes: %s", buf.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	logger.Info("Heartbeat failed")
+	if count := strings.Count(buf.String(), "] Heartbeat failed"); count != 2 {
+		t.Errorf("Expected the record to be logged again after the window, got %d: %s", count, buf.String())
+	}
+}
+
+func TestDedupHandlerFirstSeen(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewDedupHandler(NewJSONHandler(WithWriter(buf), WithSourceInfo(false)), &DedupOptions{Window: time.Minute})
+
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, message := range []string{"Connection refused", "Connection refused", "Connection refused", "Recovered"} {
+		record := NewRecord(LevelError, message)
+		record.Time = start.Add(time.Duration(i) * time.Second)
+		handler.Handle(context.Background(), record)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, `"dedup.first_seen":"2024-01-02T03:04:05Z"`) {
+		t.Errorf("Expected first_seen to be the time of the logged record: %s", output)
+	}
+	if !strings.Contains(output, `"dedup.last_seen":"2024-01-02T03:04:07Z"`) {
+		t.Errorf("Expected last_seen to be the time of the last repeat: %s", output)
+	}
+}
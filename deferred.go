@@ -0,0 +1,227 @@
+package sawmill
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// DeferredOptions configures a DeferredHandler
+type DeferredOptions struct {
+	CaptureLevel Level // Lowest level held in the buffer
+	TriggerLevel Level // Records at or above this level release the buffer
+	MaxRecords   int   // Records held before the oldest are dropped
+}
+
+// DefaultDeferredOptions returns options that hold up to 1000 debug and higher records until an error
+func DefaultDeferredOptions() *DeferredOptions {
+	return &DeferredOptions{
+		CaptureLevel: LevelDebug,
+		TriggerLevel: LevelError,
+		MaxRecords:   1000,
+	}
+}
+
+// DeferredHandler holds the records of a single unit of work, such as an HTTP
+// request, and writes them only if the work fails. Release writes the held
+// records to the wrapped handler, regardless of its level, with a shared
+// OutputID; Discard drops them. A record at or above TriggerLevel releases
+// the buffer by itself. Once released or discarded, records pass straight
+// through, and after a release they carry the same OutputID.
+type DeferredHandler struct {
+	handler Handler
+	opts    *DeferredOptions
+	state   *deferredState
+}
+
+// deferredState is shared by a DeferredHandler and its WithAttrs/WithGroup copies
+type deferredState struct {
+	mu       sync.Mutex
+	items    []deferredItem
+	dropped  int
+	released bool
+	done     bool
+	outputID string
+}
+
+// deferredItem is a held record with the handler and context it was logged with
+type deferredItem struct {
+	ctx     context.Context
+	handler Handler
+	record  *Record
+}
+
+// NewDeferredHandler wraps handler with a deferred buffer; nil options use DefaultDeferredOptions
+func NewDeferredHandler(handler Handler, opts *DeferredOptions) *DeferredHandler {
+	if opts == nil {
+		opts = DefaultDeferredOptions()
+	}
+	if opts.MaxRecords <= 0 {
+		opts.MaxRecords = DefaultDeferredOptions().MaxRecords
+	}
+
+	return &DeferredHandler{
+		handler: handler,
+		opts:    opts,
+		state:   &deferredState{},
+	}
+}
+
+// Unwrap returns the wrapped handler
+func (h *DeferredHandler) Unwrap() Handler {
+	return h.handler
+}
+
+// RetainsRecords implements RecordRetainer; held records are already clones
+func (h *DeferredHandler) RetainsRecords() bool {
+	return false
+}
+
+// Released reports whether the buffer was released by Release or a trigger record
+func (h *DeferredHandler) Released() bool {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	return h.state.released
+}
+
+// OutputID returns the correlation ID shared by released records, or "" before a release
+func (h *DeferredHandler) OutputID() string {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	return h.state.outputID
+}
+
+// Dropped returns the number of records dropped because the buffer was full
+func (h *DeferredHandler) Dropped() int {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	return h.state.dropped
+}
+
+func (h *DeferredHandler) Handle(ctx context.Context, record *Record) error {
+	s := h.state
+
+	// Valuers are resolved at log time, before the record may be held
+	if record.Level >= h.opts.CaptureLevel {
+		record.ResolveValuers()
+	}
+
+	s.mu.Lock()
+	if s.done {
+		outputID := s.outputID
+		s.mu.Unlock()
+		return h.passThrough(ctx, record, outputID)
+	}
+
+	if record.Level >= h.opts.TriggerLevel {
+		items := s.release()
+		outputID := s.outputID
+		s.mu.Unlock()
+		return errors.Join(h.replay(items, outputID), h.passThrough(ctx, record, outputID))
+	}
+
+	if record.Level >= h.opts.CaptureLevel {
+		if len(s.items) >= h.opts.MaxRecords {
+			s.items = s.items[1:]
+			s.dropped++
+		}
+		s.items = append(s.items, deferredItem{
+			ctx:     context.WithoutCancel(ctx),
+			handler: h.handler,
+			record:  record.Clone(),
+		})
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// passThrough writes a record after the buffer was released or discarded
+func (h *DeferredHandler) passThrough(ctx context.Context, record *Record, outputID string) error {
+	if outputID != "" {
+		if record.Level < h.opts.CaptureLevel && !h.handler.Enabled(ctx, record.Level) {
+			return nil
+		}
+		record.OutputID = outputID
+		record.correlated = true
+		record.levelOverride = true
+	}
+	return h.handler.Handle(ctx, record)
+}
+
+// replay writes held records with the shared OutputID
+func (h *DeferredHandler) replay(items []deferredItem, outputID string) error {
+	var errs []error
+	for _, item := range items {
+		item.record.OutputID = outputID
+		item.record.correlated = true
+		item.record.levelOverride = true
+		errs = append(errs, item.handler.Handle(item.ctx, item.record))
+	}
+	return errors.Join(errs...)
+}
+
+// release marks the state released and returns the held records; callers must hold the lock
+func (s *deferredState) release() []deferredItem {
+	items := s.items
+	s.items = nil
+	s.done = true
+	s.released = true
+	s.outputID = generateOutputID()
+	return items
+}
+
+// Release writes the held records with a shared OutputID; later records pass through with the same ID
+func (h *DeferredHandler) Release() error {
+	s := h.state
+	s.mu.Lock()
+	if s.done {
+		s.mu.Unlock()
+		return nil
+	}
+	items := s.release()
+	outputID := s.outputID
+	s.mu.Unlock()
+
+	return h.replay(items, outputID)
+}
+
+// Discard drops the held records; later records pass through unchanged
+func (h *DeferredHandler) Discard() {
+	s := h.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.done {
+		s.items = nil
+		s.done = true
+	}
+}
+
+func (h *DeferredHandler) WithAttrs(attrs []slog.Attr) Handler {
+	return &DeferredHandler{handler: h.handler.WithAttrs(attrs), opts: h.opts, state: h.state}
+}
+
+func (h *DeferredHandler) WithGroup(name string) Handler {
+	return &DeferredHandler{handler: h.handler.WithGroup(name), opts: h.opts, state: h.state}
+}
+
+// Enabled reports true for levels that are held or written
+func (h *DeferredHandler) Enabled(ctx context.Context, level Level) bool {
+	return level >= h.opts.CaptureLevel || h.handler.Enabled(ctx, level)
+}
+
+// NeedsSource reports whether the wrapped handler needs source information
+func (h *DeferredHandler) NeedsSource() bool {
+	return handlerNeedsSource(h.handler)
+}
+
+// Flush flushes the wrapped handler; held records stay held until Release or Discard
+func (h *DeferredHandler) Flush() error {
+	return flushHandler(h.handler)
+}
+
+// Close closes the wrapped handler
+func (h *DeferredHandler) Close() error {
+	return closeHandler(h.handler)
+}
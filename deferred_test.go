@@ -0,0 +1,117 @@
+package sawmill
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDeferredHandlerDiscard(t *testing.T) {
+	buf := &bytes.Buffer{}
+	base := New(NewTextHandler(WithWriter(buf)))
+	deferred := NewDeferredHandler(base.Handler(), nil)
+	logger := base.WithHandler(deferred).WithDot("request.id", "r-1")
+
+	logger.Debug("Parsed body")
+	logger.Info("Loaded user")
+	if buf.Len() != 0 {
+		t.Fatalf("Expected records to be held: %s", buf.String())
+	}
+
+	deferred.Discard()
+	logger.Info("Request completed")
+	logger.Debug("Hidden")
+
+	out := buf.String()
+	if strings.Contains(out, "Parsed body") || strings.Contains(out, "Loaded user") || strings.Contains(out, "Hidden") {
+		t.Errorf("Expected held records to be dropped: %s", out)
+	}
+	if !strings.Contains(out, "Request completed") || !strings.Contains(out, "r-1") {
+		t.Errorf("Expected records after Discard to pass through: %s", out)
+	}
+}
+
+func TestDeferredHandlerTrigger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	base := New(NewJSONHandler(WithWriter(buf), WithLevel(LevelInfo)))
+	deferred := NewDeferredHandler(base.Handler(), nil)
+	logger := base.WithHandler(deferred)
+
+	logger.Trace("Not held")
+	logger.Debug("Parsed body")
+	logger.Info("Loaded user")
+	logger.Error("Query failed")
+	logger.Debug("Cleanup")
+
+	if !deferred.Released() || deferred.OutputID() == "" {
+		t.Fatal("Expected an error record to release the buffer")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	want := []string{"Parsed body", "Loaded user", "Query failed", "Cleanup"}
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d lines, got %d: %s", len(want), len(lines), buf.String())
+	}
+	for i, line := range lines {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid JSON %q: %v", line, err)
+		}
+		if entry["message"] != want[i] || entry["output_id"] != deferred.OutputID() {
+			t.Errorf("Line %d: expected %q with the shared output ID, got %v", i, want[i], entry)
+		}
+	}
+
+	// Released and discarded buffers ignore later calls
+	deferred.Discard()
+	if err := deferred.Release(); err != nil {
+		t.Errorf("Release returned error: %v", err)
+	}
+}
+
+func TestDeferredHandlerRelease(t *testing.T) {
+	buf := &bytes.Buffer{}
+	deferred := NewDeferredHandler(NewTextHandler(WithWriter(buf)), &DeferredOptions{
+		CaptureLevel: LevelDebug,
+		TriggerLevel: LevelFatal,
+		MaxRecords:   2,
+	})
+	logger := New(deferred)
+
+	logger.Debug("First")
+	logger.Debug("Second")
+	logger.Warn("Third")
+
+	if err := deferred.Release(); err != nil {
+		t.Fatalf("Release returned error: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "First") || !strings.Contains(out, "Second") || !strings.Contains(out, "Third") {
+		t.Errorf("Expected the newest records to be released: %s", out)
+	}
+	if deferred.Dropped() != 1 {
+		t.Errorf("Expected 1 dropped record, got %d", deferred.Dropped())
+	}
+	if !deferred.Enabled(context.Background(), LevelDebug) || deferred.Enabled(context.Background(), LevelTrace) {
+		t.Error("Expected Enabled to include the capture level")
+	}
+}
+
+func TestDeferredHandlerResolvesLazyAtLogTime(t *testing.T) {
+	buf := &bytes.Buffer{}
+	base := New(NewJSONHandler(WithWriter(buf)))
+	deferred := NewDeferredHandler(base.Handler(), nil)
+	logger := base.WithHandler(deferred)
+
+	x := 1
+	logger.Info("Loaded", "x", Lazy(func() any { return x }))
+	x++
+	deferred.Release()
+
+	if !strings.Contains(buf.String(), `"x":1`) {
+		t.Errorf("Expected the released record to show the value at log time: %s", buf.String())
+	}
+}
@@ -0,0 +1,81 @@
+package sawmill
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrorHandler receives errors returned while handling a record.
+// The record is only valid for the duration of the call.
+type ErrorHandler func(err error, record *Record)
+
+// DefaultErrorHandler reports handler errors for loggers and handlers without their own ErrorHandler
+var DefaultErrorHandler = NewRateLimitedErrorHandler(os.Stderr, time.Minute)
+
+// failedWrites counts every handler error reported through reportError
+var failedWrites atomic.Uint64
+
+// FailedWrites returns the number of handler errors reported since the process started
+func FailedWrites() uint64 {
+	return failedWrites.Load()
+}
+
+// reportError counts a handler error and dispatches it to the error handler
+func reportError(handler ErrorHandler, err error, record *Record) {
+	failedWrites.Add(1)
+	notifyError(handler, err, record)
+}
+
+// notifyError dispatches an error that is not a failed write, such as a
+// configuration or argument error, to the error handler
+func notifyError(handler ErrorHandler, err error, record *Record) {
+	if handler == nil {
+		handler = DefaultErrorHandler
+	}
+	if handler != nil {
+		handler(err, record)
+	}
+}
+
+// NewRateLimitedErrorHandler returns an ErrorHandler that writes a short diagnostic
+// to w at most once per interval, counting the errors suppressed in between
+func NewRateLimitedErrorHandler(w io.Writer, interval time.Duration) ErrorHandler {
+	var (
+		mu         sync.Mutex
+		lastReport time.Time
+		suppressed uint64
+	)
+
+	return func(err error, record *Record) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now := time.Now()
+		if !lastReport.IsZero() && now.Sub(lastReport) < interval {
+			suppressed++
+			return
+		}
+
+		var line string
+		if record != nil {
+			line = fmt.Sprintf("sawmill: failed to write %s record %q: %v", levelToString(record.Level), record.Message, err)
+		} else {
+			// Errors without a record, such as configuration errors, are written as is
+			line = "sawmill: " + strings.TrimPrefix(err.Error(), "sawmill: ")
+		}
+
+		if suppressed > 0 {
+			fmt.Fprintf(w, "%s (%d earlier errors suppressed)\n", line, suppressed)
+		} else {
+			fmt.Fprintf(w, "%s\n", line)
+		}
+
+		lastReport = now
+		suppressed = 0
+	}
+}
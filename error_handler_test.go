@@ -0,0 +1,127 @@
+package sawmill
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var errTestWrite = errors.New("disk full")
+
+// failingWriter rejects every write
+type failingWriter struct{}
+
+func (w failingWriter) Write(p []byte) (int, error) {
+	return 0, errTestWrite
+}
+
+func TestLoggerWithErrorHandler(t *testing.T) {
+	var gotErr error
+	var gotMessage string
+
+	logger := New(NewTextHandler(WithWriter(failingWriter{}))).WithErrorHandler(func(err error, record *Record) {
+		gotErr = err
+		gotMessage = record.Message
+	})
+
+	before := FailedWrites()
+	logger.Info("Lost message")
+
+	if !errors.Is(gotErr, errTestWrite) {
+		t.Errorf("Expected write error to be reported, got %v", gotErr)
+	}
+	if gotMessage != "Lost message" {
+		t.Errorf("Expected failed record to be passed to error handler, got %q", gotMessage)
+	}
+	if FailedWrites() != before+1 {
+		t.Errorf("Expected FailedWrites to increase by 1, got %d -> %d", before, FailedWrites())
+	}
+
+	gotErr = nil
+	logger.As(NewJSONFormatter()).Error("Lost JSON message")
+	if !errors.Is(gotErr, errTestWrite) {
+		t.Errorf("Expected As() write error to be reported, got %v", gotErr)
+	}
+}
+
+func TestHandlerWithErrorHandler(t *testing.T) {
+	var handlerErrors, loggerErrors int
+
+	handler := NewJSONHandler(WithWriter(failingWriter{}), WithErrorHandler(func(err error, record *Record) {
+		handlerErrors++
+	}))
+	logger := New(handler).WithErrorHandler(func(err error, record *Record) {
+		loggerErrors++
+	})
+
+	logger.Info("First")
+	logger.WithDot("key", "value").Info("Second")
+
+	if handlerErrors != 2 {
+		t.Errorf("Expected handler error handler to be called twice, got %d", handlerErrors)
+	}
+	if loggerErrors != 0 {
+		t.Errorf("Expected handled errors not to reach the logger, got %d", loggerErrors)
+	}
+	if handler.FailedWrites() != 2 {
+		t.Errorf("Expected handler FailedWrites() = 2, got %d", handler.FailedWrites())
+	}
+
+	if err := handler.Handle(context.Background(), NewRecord(LevelInfo, "Direct")); err != nil {
+		t.Errorf("Expected handled error not to be returned, got %v", err)
+	}
+}
+
+func TestMultiHandlerJoinsErrors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewMultiHandler(
+		NewTextHandler(WithWriter(failingWriter{})),
+		NewTextHandler(WithWriter(buf)),
+	)
+
+	err := handler.Handle(context.Background(), NewRecord(LevelInfo, "Partially written"))
+	if !errors.Is(err, errTestWrite) {
+		t.Errorf("Expected child error to be returned, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "Partially written") {
+		t.Errorf("Expected healthy child to still write: %s", buf.String())
+	}
+}
+
+func TestRateLimitedErrorHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewRateLimitedErrorHandler(buf, 50*time.Millisecond)
+
+	record := NewRecord(LevelError, "Write failed")
+	handler(errTestWrite, record)
+	handler(errTestWrite, record)
+	handler(errTestWrite, record)
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 1 {
+		t.Fatalf("Expected a single diagnostic line, got %d: %s", lines, buf.String())
+	}
+	if !strings.Contains(buf.String(), `ERROR record "Write failed": disk full`) {
+		t.Errorf("Unexpected diagnostic: %s", buf.String())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	handler(errTestWrite, record)
+
+	if !strings.Contains(buf.String(), "2 earlier errors suppressed") {
+		t.Errorf("Expected suppressed count in second diagnostic: %s", buf.String())
+	}
+}
+
+func TestRateLimitedErrorHandlerWithoutRecord(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewRateLimitedErrorHandler(buf, time.Minute)
+
+	handler(errors.New("sawmill: invalid setting"), nil)
+
+	if buf.String() != "sawmill: invalid setting\n" {
+		t.Errorf("Unexpected diagnostic: %q", buf.String())
+	}
+}
@@ -0,0 +1,109 @@
+package sawmill
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+)
+
+const (
+	maxErrorChain = 32 // Maximum number of causes recorded for one error
+	maxErrorStack = 32 // Maximum number of frames recorded for one stack
+)
+
+// sawmillPackage is the import path prefix of the package's own functions
+var sawmillPackage = reflect.TypeOf(logger{}).PkgPath() + "."
+
+// ErrorLink describes one error in the chain of an expanded error
+type ErrorLink struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// String formats the link as "type: message"
+func (e ErrorLink) String() string {
+	return e.Type + ": " + e.Message
+}
+
+// SetError stores err under dotPath expanded into message, type and, when
+// err wraps or joins other errors, chain. Exported fields of struct errors
+// are expanded like struct arguments, so sawmill masking tags apply.
+func (f *FlatAttributes) SetError(dotPath string, err error) {
+	if err == nil {
+		f.SetByDotNotation(dotPath, nil)
+		return
+	}
+
+	f.DeleteByDotNotation(dotPath)
+	if shouldExpandStruct(err) {
+		f.ExpandStruct(dotPath, err)
+	}
+	f.SetValue(dotPath+".message", StringValue(err.Error()))
+	f.SetValue(dotPath+".type", StringValue(errorType(err)))
+	if chain := errorChain(err); len(chain) > 0 {
+		f.SetByDotNotation(dotPath+".chain", chain)
+	}
+}
+
+// errorType returns the dynamic type name of err
+func errorType(err error) string {
+	return reflect.TypeOf(err).String()
+}
+
+// errorChain follows errors.Unwrap and errors.Join depth first, excluding err itself
+func errorChain(err error) []ErrorLink {
+	var chain []ErrorLink
+	var walk func(error)
+	walk = func(err error) {
+		var causes []error
+		switch wrapped := err.(type) {
+		case interface{ Unwrap() []error }:
+			causes = wrapped.Unwrap()
+		case interface{ Unwrap() error }:
+			causes = []error{wrapped.Unwrap()}
+		}
+
+		for _, cause := range causes {
+			if cause == nil || len(chain) >= maxErrorChain {
+				continue
+			}
+			chain = append(chain, ErrorLink{Type: errorType(cause), Message: cause.Error()})
+			walk(cause)
+		}
+	}
+	walk(err)
+	return chain
+}
+
+// asError reports whether value should be expanded as an error; errors that
+// are also LogValuers are left to resolve themselves
+func asError(value interface{}) (error, bool) {
+	err, ok := value.(error)
+	if !ok || err == nil || isLogValuer(value) {
+		return nil, false
+	}
+	return err, true
+}
+
+// callerStack returns the frames of the calling goroutine from the record's
+// caller outward, formatted as "function file:line"
+func callerStack(skip int) []string {
+	var buf [maxErrorStack + maxCallerDepth]uintptr
+	pcs := callerPCs(skip, buf[:])
+	if len(pcs) > maxErrorStack {
+		pcs = pcs[:maxErrorStack]
+	}
+
+	stack := make([]string, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.PC != 0 {
+			stack = append(stack, frame.Function+" "+frame.File+":"+strconv.Itoa(frame.Line))
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
@@ -0,0 +1,176 @@
+package sawmill
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// authError is a struct error with a masked field
+type authError struct {
+	User  string
+	Token string `sawmill:"mask[3]"`
+}
+
+func (e *authError) Error() string {
+	return "authentication failed for " + e.User
+}
+
+func decodeAttributes(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", buf.String(), err)
+	}
+	attrs, _ := entry["attributes"].(map[string]interface{})
+	return attrs
+}
+
+func TestErrorExpansion(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf)))
+
+	logger.Error("Failed", "error", errors.New("boom"))
+
+	attrs := decodeAttributes(t, buf)
+	if attrs["error.message"] != "boom" {
+		t.Errorf("Expected error.message=boom, got %v", attrs["error.message"])
+	}
+	if attrs["error.type"] != "*errors.errorString" {
+		t.Errorf("Expected error.type=*errors.errorString, got %v", attrs["error.type"])
+	}
+	if _, ok := attrs["error.chain"]; ok {
+		t.Error("Expected no chain for an unwrapped error")
+	}
+	if _, ok := attrs["error.stack"]; ok {
+		t.Error("Expected no stack without WithErrorStack")
+	}
+}
+
+func TestErrorChain(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf)))
+
+	root := &fs.PathError{Op: "open", Path: "/etc/app.conf", Err: fs.ErrNotExist}
+	err := fmt.Errorf("load config: %w", root)
+	logger.Error("Failed", "err", err)
+
+	attrs := decodeAttributes(t, buf)
+	if attrs["err.message"] != err.Error() {
+		t.Errorf("Expected err.message=%q, got %v", err.Error(), attrs["err.message"])
+	}
+
+	chain, ok := attrs["err.chain"].([]interface{})
+	if !ok || len(chain) != 2 {
+		t.Fatalf("Expected a chain of two causes, got %v", attrs["err.chain"])
+	}
+	first := chain[0].(map[string]interface{})
+	if first["type"] != "*fs.PathError" || first["message"] != root.Error() {
+		t.Errorf("Unexpected first cause: %v", first)
+	}
+	second := chain[1].(map[string]interface{})
+	if second["message"] != fs.ErrNotExist.Error() {
+		t.Errorf("Unexpected second cause: %v", second)
+	}
+}
+
+func TestErrorChainJoined(t *testing.T) {
+	first := errors.New("first")
+	second := fmt.Errorf("second: %w", errors.New("cause"))
+	chain := errorChain(errors.Join(first, second))
+
+	var messages []string
+	for _, link := range chain {
+		messages = append(messages, link.Message)
+	}
+	if got := strings.Join(messages, "|"); got != "first|second: cause|cause" {
+		t.Errorf("Expected joined errors depth first, got %q", got)
+	}
+}
+
+func TestErrorStructMasking(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf)))
+
+	logger.Error("Denied", "error", &authError{User: "alice", Token: "secret-token"})
+
+	attrs := decodeAttributes(t, buf)
+	if strings.Contains(buf.String(), "secret-token") {
+		t.Errorf("Expected the token to be masked: %s", buf.String())
+	}
+	if attrs["error.token"] != "sec*********" {
+		t.Errorf("Expected a masked token, got %v", attrs["error.token"])
+	}
+	if attrs["error.user"] != "alice" || attrs["error.message"] != "authentication failed for alice" {
+		t.Errorf("Expected fields and message: %v", attrs)
+	}
+	if attrs["error.type"] != "*sawmill.authError" {
+		t.Errorf("Expected the struct type, got %v", attrs["error.type"])
+	}
+}
+
+func TestErrorStackByLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf))).WithErrorStack(LevelError)
+
+	logger.Warn("Retrying", "error", errors.New("timeout"))
+	if _, ok := decodeAttributes(t, buf)["error.stack"]; ok {
+		t.Error("Expected no stack below the stack level")
+	}
+
+	buf.Reset()
+	logger.Error("Failed", "error", errors.New("timeout"))
+	stack, ok := decodeAttributes(t, buf)["error.stack"].([]interface{})
+	if !ok || len(stack) == 0 {
+		t.Fatalf("Expected a stack at the stack level: %s", buf.String())
+	}
+	if top := stack[0].(string); !strings.Contains(top, "TestErrorStackByLevel") || !strings.Contains(top, "errors_test.go:") {
+		t.Errorf("Expected the stack to start at the caller, got %q", top)
+	}
+}
+
+func TestErrorArgumentForms(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf))).WithErrorStack(LevelError)
+	err := errors.New("boom")
+
+	logger.LogAttrs(context.Background(), LevelError, "Typed", Err(err))
+	logger.Error("slog", slog.Any("cause", err))
+	logger.WithDot("request.error", err).Info("Dot")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected three records, got %d", len(lines))
+	}
+	for i, key := range []string{"error", "cause", "request.error"} {
+		var entry map[string]interface{}
+		json.Unmarshal([]byte(lines[i]), &entry)
+		attrs := entry["attributes"].(map[string]interface{})
+		if attrs[key+".message"] != "boom" {
+			t.Errorf("Expected %s.message=boom in %s", key, lines[i])
+		}
+	}
+	if !strings.Contains(lines[0], `"error.stack":[`) || !strings.Contains(lines[1], `"cause.stack":[`) {
+		t.Errorf("Expected stacks for error records: %s", buf.String())
+	}
+}
+
+func TestErrorTextFormatting(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewTextHandler(WithWriter(buf)))
+
+	logger.Error("Failed", "error", fmt.Errorf("wrap: %w", errors.New("cause")))
+
+	output := buf.String()
+	for _, want := range []string{"wrap: cause", "*fmt.wrapError", "*errors.errorString: cause"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in text output: %s", want, output)
+		}
+	}
+}
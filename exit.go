@@ -0,0 +1,66 @@
+package sawmill
+
+import (
+	"os"
+	"sync"
+)
+
+var (
+	exitMu    sync.Mutex
+	exitFunc  = os.Exit
+	exitHooks []func()
+)
+
+// RegisterExitHook adds a function that runs before Fatal terminates the process.
+// Hooks run in registration order; a panicking hook does not stop the others.
+func RegisterExitHook(hook func()) {
+	if hook == nil {
+		return
+	}
+	exitMu.Lock()
+	defer exitMu.Unlock()
+	exitHooks = append(exitHooks, hook)
+}
+
+// SetExitFunc replaces the function Fatal uses to terminate the process and
+// returns the previous one. The default is os.Exit.
+//
+// Example usage in tests:
+//
+//	defer sawmill.SetExitFunc(sawmill.SetExitFunc(func(code int) {}))
+func SetExitFunc(fn func(code int)) func(code int) {
+	exitMu.Lock()
+	defer exitMu.Unlock()
+
+	previous := exitFunc
+	if fn == nil {
+		fn = os.Exit
+	}
+	exitFunc = fn
+	return previous
+}
+
+// exit runs exit hooks, flushes and closes the handler tree, then calls the exit function
+func exit(handler Handler, code int) {
+	exitMu.Lock()
+	hooks := make([]func(), len(exitHooks))
+	copy(hooks, exitHooks)
+	fn := exitFunc
+	exitMu.Unlock()
+
+	for _, hook := range hooks {
+		runExitHook(hook)
+	}
+
+	shutdownHandler(handler)
+
+	fn(code)
+}
+
+// runExitHook runs a hook, recovering from panics
+func runExitHook(hook func()) {
+	defer func() {
+		recover()
+	}()
+	hook()
+}
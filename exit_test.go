@@ -0,0 +1,93 @@
+package sawmill
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFatalRunsHooksFlushesAndExits(t *testing.T) {
+	dir := t.TempDir()
+
+	fileBuffer, err := NewFileBuffer(filepath.Join(dir, "app.log"), 64*1024, 0, false)
+	if err != nil {
+		t.Fatalf("NewFileBuffer failed: %v", err)
+	}
+	rotatingBuffer, err := NewRotatingFileBuffer(filepath.Join(dir, "rotating.log"), 1024*1024, 2, 64*1024)
+	if err != nil {
+		t.Fatalf("NewRotatingFileBuffer failed: %v", err)
+	}
+
+	handler := NewMultiHandler(
+		NewBaseHandler(NewTextFormatter(), fileBuffer, LevelInfo),
+		NewBaseHandler(NewJSONFormatter(), rotatingBuffer, LevelInfo),
+	)
+	logger := New(handler)
+
+	var events []string
+	RegisterExitHook(func() { events = append(events, "hook") })
+	RegisterExitHook(func() { panic("broken hook") })
+	RegisterExitHook(func() { events = append(events, "hook after panic") })
+	defer func() {
+		exitMu.Lock()
+		exitHooks = nil
+		exitMu.Unlock()
+	}()
+
+	exitCode := -1
+	defer SetExitFunc(SetExitFunc(func(code int) {
+		events = append(events, "exit")
+		exitCode = code
+	}))
+
+	logger.Fatal("Unrecoverable failure", "component", "db")
+
+	if strings.Join(events, ",") != "hook,hook after panic,exit" {
+		t.Errorf("Unexpected exit sequence: %v", events)
+	}
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+
+	for _, name := range []string{"app.log", "rotating.log"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("ReadFile failed: %v", err)
+		}
+		if !strings.Contains(string(data), "Unrecoverable failure") {
+			t.Errorf("Expected %s to be flushed before exit: %q", name, data)
+		}
+	}
+
+	if _, err := fileBuffer.Write([]byte("late")); err == nil {
+		t.Error("Expected file buffer to be closed before exit")
+	}
+}
+
+func TestFatalExitsWhenLevelDisabled(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewTextHandler(WithWriter(buf), WithLevel(LevelMark)))
+
+	exited := false
+	defer SetExitFunc(SetExitFunc(func(code int) { exited = true }))
+
+	logger.As(NewJSONFormatter()).Fatal("Filtered but fatal")
+
+	if !exited {
+		t.Error("Expected Fatal to exit even when the level is disabled")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output for disabled level: %s", buf.String())
+	}
+}
+
+func TestWriterBufferKeepsStandardStreamsOpen(t *testing.T) {
+	if err := NewWriterBuffer(os.Stdout).Close(); err != nil {
+		t.Errorf("Close returned error: %v", err)
+	}
+	if _, err := os.Stdout.Write(nil); err != nil {
+		t.Errorf("Expected stdout to remain open: %v", err)
+	}
+}
@@ -0,0 +1,593 @@
+package sawmill
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Filter is a compiled filter expression that selects records, for example
+//
+//	level >= warn && user.tier == "gold" && !has(internal.noisy)
+//
+// The fields level, message and logger refer to the record; any other name is
+// an attribute dot path. Operands are compared with ==, !=, <, <=, >, >=, =~
+// (regular expression), !~ and in [a, b, ...], and combined with &&, || and !.
+// has(path) tests whether an attribute is set, and a bare attribute path tests
+// whether it is the boolean true. Right-hand values are quoted strings,
+// numbers, true, false or bare words, which are read as strings; values
+// compared against level may be level names. Comparisons against a missing
+// attribute are false.
+type Filter struct {
+	expr string
+	root filterNode
+}
+
+// CompileFilter parses a filter expression
+func CompileFilter(expr string) (*Filter, error) {
+	p := &filterParser{input: expr}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != filterTokenEOF {
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+
+	return &Filter{expr: expr, root: root}, nil
+}
+
+// MustCompileFilter is like CompileFilter but panics if the expression is invalid
+func MustCompileFilter(expr string) *Filter {
+	filter, err := CompileFilter(expr)
+	if err != nil {
+		panic(err)
+	}
+	return filter
+}
+
+// FilterFromEnv compiles the filter expression in the named environment
+// variable; it returns nil when the variable is unset or empty
+func FilterFromEnv(name string) (*Filter, error) {
+	expr := strings.TrimSpace(os.Getenv(name))
+	if expr == "" {
+		return nil, nil
+	}
+	return CompileFilter(expr)
+}
+
+// Match reports whether the record satisfies the filter; it can be used as a RouteMatcher
+func (f *Filter) Match(record *Record) bool {
+	return f.root.eval(record)
+}
+
+// String returns the source expression
+func (f *Filter) String() string {
+	return f.expr
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (f *Filter) MarshalText() ([]byte, error) {
+	return []byte(f.expr), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using CompileFilter
+func (f *Filter) UnmarshalText(data []byte) error {
+	filter, err := CompileFilter(string(data))
+	if err != nil {
+		return err
+	}
+	*f = *filter
+	return nil
+}
+
+// FilterHandler wraps a handler and passes on only the records that match a filter
+type FilterHandler struct {
+	handler Handler
+	filter  *Filter
+}
+
+// NewFilterHandler wraps handler with filter
+func NewFilterHandler(handler Handler, filter *Filter) *FilterHandler {
+	return &FilterHandler{handler: handler, filter: filter}
+}
+
+// Unwrap returns the wrapped handler
+func (h *FilterHandler) Unwrap() Handler {
+	return h.handler
+}
+
+// Filter returns the filter applied by the handler
+func (h *FilterHandler) Filter() *Filter {
+	return h.filter
+}
+
+func (h *FilterHandler) Handle(ctx context.Context, record *Record) error {
+	if h.filter != nil && !h.filter.Match(record) {
+		return nil
+	}
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *FilterHandler) WithAttrs(attrs []slog.Attr) Handler {
+	return &FilterHandler{handler: h.handler.WithAttrs(attrs), filter: h.filter}
+}
+
+func (h *FilterHandler) WithGroup(name string) Handler {
+	return &FilterHandler{handler: h.handler.WithGroup(name), filter: h.filter}
+}
+
+func (h *FilterHandler) Enabled(ctx context.Context, level Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// NeedsSource reports whether the wrapped handler needs source information
+func (h *FilterHandler) NeedsSource() bool {
+	return handlerNeedsSource(h.handler)
+}
+
+// Flush flushes the wrapped handler
+func (h *FilterHandler) Flush() error {
+	return flushHandler(h.handler)
+}
+
+// Close closes the wrapped handler
+func (h *FilterHandler) Close() error {
+	return closeHandler(h.handler)
+}
+
+// filterNode is a node of a compiled filter expression
+type filterNode interface {
+	eval(record *Record) bool
+}
+
+type filterAnd struct{ left, right filterNode }
+
+func (n filterAnd) eval(record *Record) bool { return n.left.eval(record) && n.right.eval(record) }
+
+type filterOr struct{ left, right filterNode }
+
+func (n filterOr) eval(record *Record) bool { return n.left.eval(record) || n.right.eval(record) }
+
+type filterNot struct{ node filterNode }
+
+func (n filterNot) eval(record *Record) bool { return !n.node.eval(record) }
+
+// filterHas tests whether an attribute is set
+type filterHas struct{ path string }
+
+func (n filterHas) eval(record *Record) bool {
+	return record.Attributes.HasByDotNotation(n.path)
+}
+
+// filterTruthy tests whether a field is the boolean true
+type filterTruthy struct{ field filterField }
+
+func (n filterTruthy) eval(record *Record) bool {
+	value, ok := n.field.lookup(record)
+	b, isBool := value.(bool)
+	return ok && isBool && b
+}
+
+// filterCompare compares a field against one or more literal values
+type filterCompare struct {
+	field  filterField
+	op     string
+	values []interface{} // string, float64 or bool
+	re     *regexp.Regexp
+}
+
+func (n filterCompare) eval(record *Record) bool {
+	actual, ok := n.field.lookup(record)
+	if !ok {
+		return false
+	}
+
+	switch n.op {
+	case "=~":
+		return n.re.MatchString(filterString(actual))
+	case "!~":
+		return !n.re.MatchString(filterString(actual))
+	case "in":
+		for _, value := range n.values {
+			if filterCompareValue(actual, "==", value) {
+				return true
+			}
+		}
+		return false
+	default:
+		return filterCompareValue(actual, n.op, n.values[0])
+	}
+}
+
+// filterCompareValue applies op to an actual value and a literal
+func filterCompareValue(actual interface{}, op string, literal interface{}) bool {
+	switch lit := literal.(type) {
+	case float64:
+		n, ok := filterNumber(actual)
+		return ok && filterOrdered(n, lit, op)
+	case bool:
+		b, ok := actual.(bool)
+		if !ok {
+			return false
+		}
+		return (op == "==") == (b == lit)
+	default:
+		return filterOrdered(filterString(actual), literal.(string), op)
+	}
+}
+
+// filterOrdered applies a comparison operator to ordered values
+func filterOrdered[T cmp.Ordered](a, b T, op string) bool {
+	c := cmp.Compare(a, b)
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// filterNumber converts numeric values, and strings holding numbers, to float64
+func filterNumber(value interface{}) (float64, bool) {
+	if s, ok := value.(string); ok {
+		n, err := strconv.ParseFloat(s, 64)
+		return n, err == nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// filterString formats a value for string comparisons
+func filterString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
+
+// filterField is a record field or attribute path referenced by an expression
+type filterField struct {
+	name string // "level", "message", "logger" or "" for an attribute
+	path string
+}
+
+func newFilterField(name string) filterField {
+	switch name {
+	case "level", "message", "logger":
+		return filterField{name: name}
+	}
+	return filterField{path: name}
+}
+
+func (f filterField) lookup(record *Record) (interface{}, bool) {
+	switch f.name {
+	case "level":
+		return record.Level, true
+	case "message":
+		return record.Message, true
+	case "logger":
+		return record.LoggerName, true
+	}
+	return record.Attributes.GetByDotNotation(f.path)
+}
+
+type filterTokenKind int
+
+const (
+	filterTokenEOF filterTokenKind = iota
+	filterTokenIdent
+	filterTokenString
+	filterTokenNumber
+	filterTokenOp
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+}
+
+// filterParser is a recursive descent parser over the tokenized expression
+type filterParser struct {
+	input  string
+	tokens []filterToken
+	next   int
+}
+
+func (p *filterParser) errorf(tok filterToken, format string, args ...interface{}) error {
+	return fmt.Errorf("sawmill: filter %q: %s at position %d", p.input, fmt.Sprintf(format, args...), tok.pos)
+}
+
+// filterOperators lists the operator tokens, longest first
+var filterOperators = []string{"==", "!=", "<=", ">=", "=~", "!~", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ","}
+
+func (p *filterParser) tokenize() error {
+	s := p.input
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(s) && s[end] != c {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return p.errorf(filterToken{pos: i}, "unterminated string")
+			}
+			text := s[i+1 : end]
+			if c == '"' {
+				unquoted, err := strconv.Unquote(s[i : end+1])
+				if err != nil {
+					return p.errorf(filterToken{pos: i}, "invalid string %s", s[i:end+1])
+				}
+				text = unquoted
+			}
+			p.tokens = append(p.tokens, filterToken{kind: filterTokenString, text: text, pos: i})
+			i = end + 1
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '9':
+			end := i + 1
+			for end < len(s) && isFilterIdentByte(s[end]) {
+				end++
+			}
+			p.tokens = append(p.tokens, filterToken{kind: filterTokenNumber, text: s[i:end], pos: i})
+			i = end
+		case isFilterIdentByte(c):
+			end := i
+			for end < len(s) && isFilterIdentByte(s[end]) {
+				end++
+			}
+			p.tokens = append(p.tokens, filterToken{kind: filterTokenIdent, text: s[i:end], pos: i})
+			i = end
+		default:
+			op := ""
+			for _, candidate := range filterOperators {
+				if strings.HasPrefix(s[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return p.errorf(filterToken{pos: i}, "unexpected character %q", c)
+			}
+			p.tokens = append(p.tokens, filterToken{kind: filterTokenOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	p.tokens = append(p.tokens, filterToken{kind: filterTokenEOF, pos: len(s)})
+	return nil
+}
+
+func isFilterIdentByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '-'
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.next]
+}
+
+func (p *filterParser) advance() filterToken {
+	tok := p.tokens[p.next]
+	if tok.kind != filterTokenEOF {
+		p.next++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is the given operator
+func (p *filterParser) accept(op string) bool {
+	if tok := p.peek(); tok.kind == filterTokenOp && tok.text == op {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) expect(op string) error {
+	if !p.accept(op) {
+		tok := p.peek()
+		return p.errorf(tok, "expected %q", op)
+	}
+	return nil
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = filterOr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = filterAnd{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	if p.accept("!") {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return filterNot{node: node}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *filterParser) parsePrimary() (filterNode, error) {
+	if p.accept("(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	}
+
+	tok := p.advance()
+	if tok.kind != filterTokenIdent {
+		return nil, p.errorf(tok, "expected a field name, got %q", tok.text)
+	}
+
+	if tok.text == "has" && p.accept("(") {
+		path := p.advance()
+		if path.kind != filterTokenIdent {
+			return nil, p.errorf(path, "expected an attribute path, got %q", path.text)
+		}
+		return filterHas{path: path.text}, p.expect(")")
+	}
+
+	field := newFilterField(tok.text)
+	op := p.peek()
+	switch {
+	case op.kind == filterTokenIdent && op.text == "in":
+		p.next++
+		return p.parseIn(field)
+	case op.kind == filterTokenOp && isFilterComparison(op.text):
+		p.next++
+		return p.parseComparison(field, op)
+	}
+	return filterTruthy{field: field}, nil
+}
+
+func isFilterComparison(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=", "=~", "!~":
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseComparison(field filterField, op filterToken) (filterNode, error) {
+	valueTok := p.peek()
+	regex := op.text == "=~" || op.text == "!~"
+	value, err := p.parseValue(field, !regex)
+	if err != nil {
+		return nil, err
+	}
+
+	node := filterCompare{field: field, op: op.text, values: []interface{}{value}}
+	switch op.text {
+	case "=~", "!~":
+		pattern, ok := value.(string)
+		if !ok {
+			pattern = valueTok.text
+		}
+		if node.re, err = regexp.Compile(pattern); err != nil {
+			return nil, p.errorf(valueTok, "invalid regular expression: %v", err)
+		}
+	case "<", "<=", ">", ">=":
+		if _, ok := value.(bool); ok {
+			return nil, p.errorf(op, "operator %s does not apply to booleans", op.text)
+		}
+	}
+	return node, nil
+}
+
+func (p *filterParser) parseIn(field filterField) (filterNode, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+
+	node := filterCompare{field: field, op: "in"}
+	for !p.accept("]") {
+		if len(node.values) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		value, err := p.parseValue(field, true)
+		if err != nil {
+			return nil, err
+		}
+		node.values = append(node.values, value)
+	}
+	return node, nil
+}
+
+// parseValue reads a literal; with levelNames set, names compared against level become level numbers
+func (p *filterParser) parseValue(field filterField, levelNames bool) (interface{}, error) {
+	tok := p.advance()
+
+	var value interface{}
+	switch tok.kind {
+	case filterTokenString:
+		value = tok.text
+	case filterTokenNumber:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf(tok, "invalid number %q", tok.text)
+		}
+		value = n
+	case filterTokenIdent:
+		switch tok.text {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		default:
+			value = tok.text
+		}
+	default:
+		return nil, p.errorf(tok, "expected a value, got %q", tok.text)
+	}
+
+	if s, ok := value.(string); ok && levelNames && field.name == "level" {
+		level, err := ParseLevel(s)
+		if err != nil {
+			return nil, p.errorf(tok, "unknown level %q", s)
+		}
+		value = float64(level)
+	}
+	return value, nil
+}
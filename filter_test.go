@@ -0,0 +1,171 @@
+package sawmill
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFilterMatch(t *testing.T) {
+	record := NewRecord(LevelWarn, "Payment declined")
+	record.LoggerName = "billing.stripe"
+	record.Attributes.SetByDotNotation("user.tier", "gold")
+	record.Attributes.SetByDotNotation("user.age", 42)
+	record.Attributes.SetByDotNotation("retry", true)
+	record.Attributes.SetByDotNotation("region", "eu-west-1")
+
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		{`level >= warn`, true},
+		{`level > WARN`, false},
+		{`level == "warning"`, true},
+		{`level in [error, warn]`, true},
+		{`level =~ "^(WARN|ERROR)$"`, true},
+		{`message == "Payment declined"`, true},
+		{`message =~ 'declin'`, true},
+		{`logger =~ "^billing\\."`, true},
+		{`user.tier == "gold"`, true},
+		{`user.tier == gold`, true},
+		{`user.tier != "gold"`, false},
+		{`user.tier in ["silver", "gold"]`, true},
+		{`user.age >= 18 && user.age < 65`, true},
+		{`user.age == 42.0`, true},
+		{`retry`, true},
+		{`retry == false`, false},
+		{`!retry`, false},
+		{`has(user.tier)`, true},
+		{`has(internal.noisy)`, false},
+		{`!has(internal.noisy)`, true},
+		{`missing == "x" || missing != "x"`, false},
+		{`region !~ "^us-"`, true},
+		{`level >= warn && user.tier == "gold" && !has(internal.noisy)`, true},
+		{`level >= error || (user.tier == "gold" && retry)`, true},
+		{`!(level >= warn) || user.age < 18`, false},
+	}
+
+	for _, tc := range cases {
+		filter, err := CompileFilter(tc.expr)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.expr, err)
+			continue
+		}
+		if got := filter.Match(record); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.expr, tc.want, got)
+		}
+	}
+}
+
+func TestCompileFilterErrors(t *testing.T) {
+	exprs := []string{
+		``,
+		`level >=`,
+		`level >= loud`,
+		`user.tier == "gold`,
+		`(level >= warn`,
+		`user.tier in ["gold"`,
+		`message =~ "("`,
+		`retry < true`,
+		`level >= warn extra`,
+		`user.tier # "gold"`,
+	}
+
+	for _, expr := range exprs {
+		if _, err := CompileFilter(expr); err == nil {
+			t.Errorf("%q: expected an error", expr)
+		} else if !strings.HasPrefix(err.Error(), "sawmill: filter") {
+			t.Errorf("%q: unexpected error format: %v", expr, err)
+		}
+	}
+}
+
+func TestFilterHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	filter := MustCompileFilter(`level >= warn || audit`)
+	logger := New(NewFilterHandler(NewTextHandler(WithWriter(buf)), filter))
+
+	logger.Info("Routine")
+	logger.Info("Audited", "audit", true)
+	logger.Warn("Warning")
+
+	out := buf.String()
+	if strings.Contains(out, "Routine") || !strings.Contains(out, "Audited") || !strings.Contains(out, "Warning") {
+		t.Errorf("Unexpected filtered output: %s", out)
+	}
+}
+
+func TestFilterHandlerOptions(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf), WithFilter(MustCompileFilter(`user.tier == "gold"`))))
+
+	logger.Info("Gold", "user.tier", "gold")
+	logger.Info("Silver", "user.tier", "silver")
+
+	if !strings.Contains(buf.String(), "Gold") || strings.Contains(buf.String(), "Silver") {
+		t.Errorf("Unexpected filtered output: %s", buf.String())
+	}
+
+	buf.Reset()
+	opts := NewSawmillOptions(WithLogFilter(`!has(internal.noisy)`))
+	logger = New(NewJSONHandler(WithWriter(buf), WithSawmillOptions(opts)))
+
+	logger.Info("Noisy", "internal.noisy", true)
+	logger.Info("Quiet")
+
+	if strings.Contains(buf.String(), "Noisy") || !strings.Contains(buf.String(), "Quiet") {
+		t.Errorf("Unexpected filtered output: %s", buf.String())
+	}
+}
+
+func TestInvalidFilterOptionIsAConfigError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var reported []error
+	before := FailedWrites()
+
+	opts := NewSawmillOptions(WithLogFilter(`level >=`))
+	logger := New(NewJSONHandler(WithWriter(buf), WithSawmillOptions(opts), WithErrorHandler(func(err error, record *Record) {
+		reported = append(reported, err)
+	})))
+	logger.Info("Unfiltered")
+
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "filter") {
+		t.Errorf("Expected the invalid filter to be reported once, got %v", reported)
+	}
+	if FailedWrites() != before {
+		t.Errorf("Expected an invalid filter not to count as a failed write, got %d -> %d", before, FailedWrites())
+	}
+	if !strings.Contains(buf.String(), "Unfiltered") {
+		t.Errorf("Expected records to be written unfiltered: %s", buf.String())
+	}
+}
+
+func TestFilterConfig(t *testing.T) {
+	opts := NewSawmillOptions(WithLogFilter(`level >=`))
+	if err := opts.Validate(); err == nil {
+		t.Error("Expected Validate to reject an invalid filter")
+	}
+
+	var config struct {
+		Filter *Filter `json:"filter"`
+	}
+	if err := json.Unmarshal([]byte(`{"filter":"level >= error"}`), &config); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if !config.Filter.Match(NewRecord(LevelError, "Boom")) || config.Filter.Match(NewRecord(LevelInfo, "Fine")) {
+		t.Error("Expected the unmarshaled filter to match by level")
+	}
+	if data, _ := json.Marshal(config); string(data) != `{"filter":"level \u003e= error"}` {
+		t.Errorf("Unexpected marshaled filter: %s", data)
+	}
+
+	t.Setenv("SAWMILL_TEST_FILTER", `message =~ "^Boom"`)
+	filter, err := FilterFromEnv("SAWMILL_TEST_FILTER")
+	if err != nil || filter == nil || !filter.Match(NewRecord(LevelInfo, "Boom")) {
+		t.Errorf("Expected a filter from the environment, got %v, %v", filter, err)
+	}
+	if filter, err := FilterFromEnv("SAWMILL_TEST_FILTER_UNSET"); filter != nil || err != nil {
+		t.Errorf("Expected no filter for an unset variable, got %v, %v", filter, err)
+	}
+}
@@ -3,8 +3,10 @@ package sawmill
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -22,6 +24,17 @@ type FlatAttributes struct {
 		value interface{}
 	}
 	smallCount int
+
+	// Typed values set from Attr and slog.Attr, encoded without boxing
+	typed []typedAttr
+
+	hasValuers bool // Set when a value is a LogValuer awaiting resolution
+}
+
+// typedAttr is a typed value stored under a dot notation key
+type typedAttr struct {
+	key   string
+	value Value
 }
 
 // NewFlatAttributes creates a new FlatAttributes instance
@@ -50,10 +63,88 @@ func (f *FlatAttributes) SetByDotNotation(dotPath string, value interface{}) {
 		f.data = make(map[string]interface{}, 16)
 	}
 	f.data[dotPath] = value
+	f.removeTyped(dotPath)
+	if isLogValuer(value) {
+		f.hasValuers = true
+	}
+}
+
+// SetValue sets a typed value using dot notation key; Any values are stored like SetByDotNotation
+func (f *FlatAttributes) SetValue(dotPath string, value Value) {
+	if value.kind == KindError {
+		f.SetError(dotPath, value.any.(error))
+		return
+	}
+	if value.kind == KindAny {
+		if err, ok := asError(value.any); ok {
+			f.SetError(dotPath, err)
+		} else if shouldExpandStruct(value.any) {
+			f.ExpandStruct(dotPath, value.any)
+		} else {
+			f.SetByDotNotation(dotPath, value.any)
+		}
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.setTyped(dotPath, value)
+}
+
+// setTyped stores a typed value, replacing any value under the same key;
+// callers must hold the lock
+func (f *FlatAttributes) setTyped(dotPath string, value Value) {
+	for i := range f.typed {
+		if f.typed[i].key == dotPath {
+			f.typed[i].value = value
+			return
+		}
+	}
+
+	if f.data != nil {
+		delete(f.data, dotPath)
+	}
+	f.removeSmall(dotPath)
+	f.typed = append(f.typed, typedAttr{key: dotPath, value: value})
+}
+
+// removeTyped removes a typed value; callers must hold the lock
+func (f *FlatAttributes) removeTyped(dotPath string) bool {
+	for i := range f.typed {
+		if f.typed[i].key == dotPath {
+			last := len(f.typed) - 1
+			copy(f.typed[i:], f.typed[i+1:])
+			f.typed[last] = typedAttr{}
+			f.typed = f.typed[:last]
+			return true
+		}
+	}
+	return false
+}
+
+// removeSmall removes a small-data entry; callers must hold the lock
+func (f *FlatAttributes) removeSmall(dotPath string) bool {
+	for i := 0; i < f.smallCount; i++ {
+		if f.smallData[i].key == dotPath {
+			copy(f.smallData[i:f.smallCount], f.smallData[i+1:f.smallCount])
+			f.smallCount--
+			f.smallData[f.smallCount].key = ""
+			f.smallData[f.smallCount].value = nil
+			return true
+		}
+	}
+	return false
 }
 
 // SetFast is an optimized version for single-level keys (no locking for performance)
 func (f *FlatAttributes) SetFast(key string, value interface{}) {
+	if isLogValuer(value) {
+		f.hasValuers = true
+	}
+	if len(f.typed) > 0 {
+		f.removeTyped(key)
+	}
+
 	// Fast path: use small array for few attributes to avoid map allocation
 	if f.data == nil && f.smallCount < len(f.smallData) {
 		// Check if key already exists in small data
@@ -82,6 +173,37 @@ func (f *FlatAttributes) SetFast(key string, value interface{}) {
 	f.data[key] = value
 }
 
+// SetAttr sets a slog attribute under the given group path, flattening
+// slog.Group values into dot notation keys
+func (f *FlatAttributes) SetAttr(groups []string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() == slog.KindGroup {
+		members := attr.Value.Group()
+		if len(members) == 0 {
+			return
+		}
+
+		// Groups with an empty key are inlined into the parent
+		path := groups
+		if attr.Key != "" {
+			path = append(groups[:len(groups):len(groups)], attr.Key)
+		}
+		for _, member := range members {
+			f.SetAttr(path, member)
+		}
+		return
+	}
+
+	// Empty attributes are ignored, matching slog
+	if attr.Key == "" && attr.Value.Any() == nil {
+		return
+	}
+
+	keyPath := append(groups[:len(groups):len(groups)], attr.Key)
+	f.SetValue(strings.Join(keyPath, "."), valueFromSlog(attr.Value))
+}
+
 // Get retrieves a value at the given key path
 func (f *FlatAttributes) Get(keyPath []string) (interface{}, bool) {
 	key := strings.Join(keyPath, ".")
@@ -100,6 +222,12 @@ func (f *FlatAttributes) GetByDotNotation(dotPath string) (interface{}, bool) {
 		}
 	}
 
+	for i := range f.typed {
+		if f.typed[i].key == dotPath {
+			return f.typed[i].value.Any(), true
+		}
+	}
+
 	if f.data == nil {
 		return nil, false
 	}
@@ -130,6 +258,10 @@ func (f *FlatAttributes) DeleteByDotNotation(dotPath string) bool {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
+	if f.removeSmall(dotPath) || f.removeTyped(dotPath) {
+		return true
+	}
+
 	if f.data == nil {
 		return false
 	}
@@ -146,14 +278,17 @@ func (f *FlatAttributes) Keys() []string {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
-	if f.data == nil {
+	if f.data == nil && len(f.typed) == 0 {
 		return nil
 	}
 
-	keys := make([]string, 0, len(f.data))
+	keys := make([]string, 0, len(f.data)+len(f.typed))
 	for key := range f.data {
 		keys = append(keys, key)
 	}
+	for i := range f.typed {
+		keys = append(keys, f.typed[i].key)
+	}
 	return keys
 }
 
@@ -172,10 +307,7 @@ func (f *FlatAttributes) Size() int {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
-	if f.data == nil {
-		return f.smallCount
-	}
-	return len(f.data) + f.smallCount
+	return len(f.data) + f.smallCount + len(f.typed)
 }
 
 // IsEmpty checks if the map is empty
@@ -183,17 +315,13 @@ func (f *FlatAttributes) IsEmpty() bool {
 	return f.Size() == 0
 }
 
-// Clone creates a shallow copy of the attributes
+// Clone creates a copy of the attributes, including the small-data entries
 func (f *FlatAttributes) Clone() *FlatAttributes {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
 	clone := NewFlatAttributes()
-	if f.data != nil {
-		for key, value := range f.data {
-			clone.data[key] = value
-		}
-	}
+	f.copyInto(clone)
 	return clone
 }
 
@@ -203,14 +331,26 @@ func (f *FlatAttributes) CloneFromPool() *FlatAttributes {
 	defer f.mu.RUnlock()
 
 	clone := NewFlatAttributesFromPool()
-	if f.data != nil {
-		for key, value := range f.data {
-			clone.data[key] = value
-		}
-	}
+	f.copyInto(clone)
 	return clone
 }
 
+// copyInto copies the map and small-data entries into an empty clone;
+// callers must hold the read lock
+func (f *FlatAttributes) copyInto(clone *FlatAttributes) {
+	if f.data == nil {
+		// Keep small-data-only attributes off the map path
+		clone.data = nil
+	}
+	for key, value := range f.data {
+		clone.data[key] = value
+	}
+	clone.smallData = f.smallData
+	clone.smallCount = f.smallCount
+	clone.typed = append(clone.typed[:0], f.typed...)
+	clone.hasValuers = f.hasValuers
+}
+
 // Merge combines another FlatAttributes into this one
 func (f *FlatAttributes) Merge(other *FlatAttributes) {
 	if other == nil || other.IsEmpty() {
@@ -229,6 +369,20 @@ func (f *FlatAttributes) Merge(other *FlatAttributes) {
 	for key, value := range other.data {
 		f.data[key] = value
 	}
+	for i := 0; i < other.smallCount; i++ {
+		f.data[other.smallData[i].key] = other.smallData[i].value
+	}
+	if len(f.typed) > 0 {
+		for key := range other.data {
+			f.removeTyped(key)
+		}
+	}
+	for i := range other.typed {
+		f.setTyped(other.typed[i].key, other.typed[i].value)
+	}
+	if other.hasValuers {
+		f.hasValuers = true
+	}
 }
 
 // Walk traverses all key-value pairs and calls the provided function
@@ -236,19 +390,21 @@ func (f *FlatAttributes) Walk(fn func(path []string, value interface{})) {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
-	// Walk small data first
-	for i := 0; i < f.smallCount; i++ {
-		path := strings.Split(f.smallData[i].key, ".")
-		fn(path, f.smallData[i].value)
-	}
+	f.each(func(key string, value interface{}) {
+		fn(strings.Split(key, "."), value)
+	})
+}
 
-	if f.data == nil {
-		return
+// each calls fn for the small data, map and typed entries; callers must hold the read lock
+func (f *FlatAttributes) each(fn func(key string, value interface{})) {
+	for i := 0; i < f.smallCount; i++ {
+		fn(f.smallData[i].key, f.smallData[i].value)
 	}
-
 	for key, value := range f.data {
-		path := strings.Split(key, ".")
-		fn(path, value)
+		fn(key, value)
+	}
+	for i := range f.typed {
+		fn(f.typed[i].key, f.typed[i].value.Any())
 	}
 }
 
@@ -257,14 +413,10 @@ func (f *FlatAttributes) ToMap() map[string]interface{} {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
-	if f.data == nil {
-		return make(map[string]interface{})
-	}
-
-	result := make(map[string]interface{}, len(f.data))
-	for key, value := range f.data {
+	result := make(map[string]interface{}, len(f.data)+len(f.typed))
+	f.each(func(key string, value interface{}) {
 		result[key] = value
-	}
+	})
 	return result
 }
 
@@ -273,13 +425,9 @@ func (f *FlatAttributes) ToNestedMap() map[string]interface{} {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
-	if f.data == nil {
-		return make(map[string]interface{})
-	}
-
 	result := make(map[string]interface{})
 
-	for flatKey, value := range f.data {
+	f.each(func(flatKey string, value interface{}) {
 		parts := strings.Split(flatKey, ".")
 		current := result
 
@@ -308,43 +456,78 @@ func (f *FlatAttributes) ToNestedMap() map[string]interface{} {
 
 		// Set the final value
 		current[parts[len(parts)-1]] = value
-	}
+	})
 
 	return result
 }
 
+// jsonEntry is an attribute queued for sorted JSON encoding
+type jsonEntry struct {
+	key   string
+	value interface{}
+	typed *Value
+}
+
+var jsonEntryPool = sync.Pool{
+	New: func() interface{} {
+		entries := make([]jsonEntry, 0, 16)
+		return &entries
+	},
+}
+
 // MarshalJSON implements json.Marshaler for efficient JSON encoding
 func (f *FlatAttributes) MarshalJSON() ([]byte, error) {
+	return f.AppendJSON(nil)
+}
+
+// AppendJSON appends the attributes to dst as a flat JSON object with sorted
+// keys. Typed values and common Go types are encoded without reflection.
+func (f *FlatAttributes) AppendJSON(dst []byte) ([]byte, error) {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
-	if f.data == nil && f.smallCount == 0 {
-		return []byte("{}"), nil
-	}
+	entriesPtr := jsonEntryPool.Get().(*[]jsonEntry)
+	entries := (*entriesPtr)[:0]
+	defer func() {
+		clear(entries)
+		*entriesPtr = entries[:0]
+		jsonEntryPool.Put(entriesPtr)
+	}()
 
-	// Fast path for small data only
-	if f.data == nil {
-		smallMap := make(map[string]interface{}, f.smallCount)
-		for i := 0; i < f.smallCount; i++ {
-			smallMap[f.smallData[i].key] = f.smallData[i].value
-		}
-		return json.Marshal(smallMap)
+	for i := 0; i < f.smallCount; i++ {
+		entries = append(entries, jsonEntry{key: f.smallData[i].key, value: f.smallData[i].value})
 	}
+	for key, value := range f.data {
+		entries = append(entries, jsonEntry{key: key, value: value})
+	}
+	for i := range f.typed {
+		entries = append(entries, jsonEntry{key: f.typed[i].key, typed: &f.typed[i].value})
+	}
+	slices.SortStableFunc(entries, func(a, b jsonEntry) int {
+		return strings.Compare(a.key, b.key)
+	})
 
-	// Combined path: merge small data with map
-	if f.smallCount > 0 {
-		combinedMap := make(map[string]interface{}, len(f.data)+f.smallCount)
-		for k, v := range f.data {
-			combinedMap[k] = v
+	var err error
+	dst = append(dst, '{')
+	for i, entry := range entries {
+		if i > 0 {
+			if entry.key == entries[i-1].key {
+				continue
+			}
+			dst = append(dst, ',')
 		}
-		for i := 0; i < f.smallCount; i++ {
-			combinedMap[f.smallData[i].key] = f.smallData[i].value
+		dst = appendJSONString(dst, entry.key)
+		dst = append(dst, ':')
+		if entry.typed != nil {
+			dst, err = appendJSONValue(dst, *entry.typed)
+		} else {
+			dst, err = appendJSONAny(dst, entry.value)
+		}
+		if err != nil {
+			return nil, err
 		}
-		return json.Marshal(combinedMap)
 	}
-
-	// Use direct JSON marshaling of the flat map for performance
-	return json.Marshal(f.data)
+	return append(dst, '}'), nil
 }
 
 // MarshalNestedJSON creates nested JSON structure from flat keys
@@ -358,7 +541,7 @@ func (f *FlatAttributes) String() string {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
-	if f.data == nil || len(f.data) == 0 {
+	if len(f.data) == 0 && len(f.typed) == 0 {
 		return "{}"
 	}
 
@@ -366,7 +549,7 @@ func (f *FlatAttributes) String() string {
 	builder.WriteString("{")
 	first := true
 
-	for key, value := range f.data {
+	f.each(func(key string, value interface{}) {
 		if !first {
 			builder.WriteString(", ")
 		}
@@ -374,7 +557,7 @@ func (f *FlatAttributes) String() string {
 		builder.WriteString(": ")
 		builder.WriteString(fmt.Sprintf("%v", value))
 		first = false
-	}
+	})
 
 	builder.WriteString("}")
 	return builder.String()
@@ -396,6 +579,11 @@ func (f *FlatAttributes) reset() {
 		f.smallData[i].value = nil
 	}
 	f.smallCount = 0
+
+	// Clear typed values but keep the allocation
+	clear(f.typed)
+	f.typed = f.typed[:0]
+	f.hasValuers = false
 }
 
 // maskValue applies masking to a field value based on the sawmill tag
@@ -483,8 +671,10 @@ func (f *FlatAttributes) ExpandStruct(prefix string, value interface{}) {
 		// Check for sawmill struct tag for masking
 		sawmillTag := fieldType.Tag.Get("sawmill")
 		
-		// Recursively expand nested structs
-		if field.Kind() == reflect.Struct || (field.Kind() == reflect.Ptr && !field.IsNil() && field.Elem().Kind() == reflect.Struct) {
+		// Expand errors, then recursively expand nested structs
+		if err, ok := asError(fieldValue); ok {
+			f.SetError(fieldKey, err)
+		} else if field.Kind() == reflect.Struct || (field.Kind() == reflect.Ptr && !field.IsNil() && field.Elem().Kind() == reflect.Struct) {
 			f.ExpandStruct(fieldKey, fieldValue)
 		} else {
 			// Apply masking if sawmill tag contains mask directive
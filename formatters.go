@@ -71,6 +71,20 @@ func (f *JSONFormatter) Format(record *Record) ([]byte, error) {
 		buf.WriteByte('"')
 	}
 
+	// Write logger name
+	if record.LoggerName != "" {
+		buf.WriteString(`,"logger":"`)
+		f.writeJSONEscapedString(buf, record.LoggerName)
+		buf.WriteByte('"')
+	}
+
+	// Write output ID
+	if outputID := record.correlatedOutputID(); outputID != "" {
+		buf.WriteString(`,"output_id":"`)
+		f.writeJSONEscapedString(buf, outputID)
+		buf.WriteByte('"')
+	}
+
 	// Write source
 	if f.IncludeSource && record.PC != 0 {
 		if frame, ok := f.getFrame(record.PC); ok {
@@ -85,7 +99,7 @@ func (f *JSONFormatter) Format(record *Record) ([]byte, error) {
 		}
 	}
 
-	// Write attributes using optimized MarshalJSON
+	// Write attributes using the reflection-free encoder
 	if !record.Attributes.IsEmpty() {
 		attributesKey := f.AttributesKey
 		if attributesKey == "" {
@@ -97,7 +111,7 @@ func (f *JSONFormatter) Format(record *Record) ([]byte, error) {
 		buf.WriteString(attributesKey)
 		buf.WriteString(`":`)
 
-		attrBytes, err := record.Attributes.MarshalJSON()
+		attrBytes, err := record.Attributes.AppendJSON(buf.AvailableBuffer())
 		if err != nil {
 			return nil, err
 		}
@@ -117,6 +131,12 @@ func (f *JSONFormatter) Format(record *Record) ([]byte, error) {
 		if f.IncludeLevel {
 			output["level"] = f.levelString(record.Level)
 		}
+		if record.LoggerName != "" {
+			output["logger"] = record.LoggerName
+		}
+		if outputID := record.correlatedOutputID(); outputID != "" {
+			output["output_id"] = outputID
+		}
 		if f.IncludeSource && record.PC != 0 {
 			if frame, ok := f.getFrame(record.PC); ok {
 				output["source"] = map[string]interface{}{
@@ -171,6 +191,8 @@ type XMLRecord struct {
 	XMLName    xml.Name   `xml:"record"`
 	Timestamp  string     `xml:"timestamp"`
 	Level      string     `xml:"level,omitempty"`
+	Logger     string     `xml:"logger,omitempty"`
+	OutputID   string     `xml:"output_id,omitempty"`
 	Message    string     `xml:"message"`
 	Source     *XMLSource `xml:"source,omitempty"`
 	Attributes string     `xml:"attributes,omitempty"`
@@ -195,6 +217,8 @@ func NewXMLFormatter() *XMLFormatter {
 func (f *XMLFormatter) Format(record *Record) ([]byte, error) {
 	xmlRecord := XMLRecord{
 		Timestamp: record.Time.Format(f.TimeFormat),
+		Logger:    record.LoggerName,
+		OutputID:  record.correlatedOutputID(),
 		Message:   record.Message,
 	}
 
@@ -263,6 +287,14 @@ func (f *YAMLFormatter) Format(record *Record) ([]byte, error) {
 		output.WriteString(fmt.Sprintf("level: %s\n", f.levelString(record.Level)))
 	}
 
+	if record.LoggerName != "" {
+		output.WriteString(fmt.Sprintf("logger: %s\n", record.LoggerName))
+	}
+
+	if outputID := record.correlatedOutputID(); outputID != "" {
+		output.WriteString(fmt.Sprintf("output_id: %s\n", outputID))
+	}
+
 	output.WriteString(fmt.Sprintf("message: %q\n", record.Message))
 
 	if f.IncludeSource && record.PC != 0 {
@@ -349,12 +381,20 @@ func (f *TextFormatter) Format(record *Record) ([]byte, error) {
 		output.WriteString(fmt.Sprintf(" [%s]", level))
 	}
 
+	if outputID := record.correlatedOutputID(); outputID != "" {
+		output.WriteString(fmt.Sprintf(" (%s)", outputID))
+	}
+
 	if f.IncludeSource && record.PC != 0 {
 		if frame, ok := f.getFrame(record.PC); ok {
 			output.WriteString(fmt.Sprintf(" %s:%d", frame.File, frame.Line))
 		}
 	}
 
+	if record.LoggerName != "" {
+		output.WriteString(fmt.Sprintf(" %s:", record.LoggerName))
+	}
+
 	output.WriteString(fmt.Sprintf(" %s", record.Message))
 	if !record.Attributes.IsEmpty() {
 		if f.ColorOutput && f.ColorScheme != nil {
@@ -371,7 +411,7 @@ func (f *TextFormatter) Format(record *Record) ([]byte, error) {
 	}
 
 	output.WriteString("\n")
-	return []byte(output.String()), nil
+	return []byte(indentLines(output.String(), record.sectionDepth)), nil
 }
 
 func (f *TextFormatter) formatMark(record *Record) ([]byte, error) {
@@ -395,6 +435,9 @@ func (f *TextFormatter) formatMark(record *Record) ([]byte, error) {
 	} else {
 		output.WriteString(fmt.Sprintf("%s\n", separator))
 		output.WriteString(fmt.Sprintf(" MARKED @ %s ", record.Time.Format(f.TimeFormat)))
+		if record.Message != "" {
+			output.WriteString(record.Message)
+		}
 	}
 
 	if !record.Attributes.IsEmpty() {
@@ -419,7 +462,7 @@ func (f *TextFormatter) formatMark(record *Record) ([]byte, error) {
 		output.WriteString(fmt.Sprintf("%s\n", separator))
 	}
 
-	return []byte(output.String()), nil
+	return []byte(indentLines(output.String(), record.sectionDepth)), nil
 }
 
 func (f *TextFormatter) writeTextAttributesFlat(output *strings.Builder, attrs *FlatAttributes) {
@@ -533,8 +576,8 @@ func (f *TextFormatter) getFrame(pc uintptr) (runtime.Frame, bool) {
 
 func getFrame(pc uintptr) (runtime.Frame, bool) {
 	frames := runtime.CallersFrames([]uintptr{pc})
-	frame, ok := frames.Next()
-	return frame, ok
+	frame, _ := frames.Next()
+	return frame, frame.PC != 0
 }
 
 // NewJSONFormatterWithKey creates a JSON formatter with a custom attributes key
@@ -617,6 +660,26 @@ func (f *KeyValueFormatter) Format(record *Record) ([]byte, error) {
 		}
 	}
 
+	// Add logger name
+	if record.LoggerName != "" {
+		if f.ColorOutput && f.ColorScheme != nil {
+			output.WriteString(" ")
+			output.WriteString(f.formatKeyValue("logger", record.LoggerName, false))
+		} else {
+			output.WriteString(fmt.Sprintf(" logger=%s", record.LoggerName))
+		}
+	}
+
+	// Add output ID
+	if outputID := record.correlatedOutputID(); outputID != "" {
+		if f.ColorOutput && f.ColorScheme != nil {
+			output.WriteString(" ")
+			output.WriteString(f.formatKeyValue("output_id", outputID, false))
+		} else {
+			output.WriteString(fmt.Sprintf(" output_id=%s", outputID))
+		}
+	}
+
 	// Add source
 	if f.IncludeSource && record.PC != 0 {
 		if frame, ok := f.getFrame(record.PC); ok {
@@ -644,7 +707,7 @@ func (f *KeyValueFormatter) Format(record *Record) ([]byte, error) {
 	}
 
 	output.WriteString("\n")
-	return []byte(output.String()), nil
+	return []byte(indentLines(output.String(), record.sectionDepth)), nil
 }
 
 func (f *KeyValueFormatter) formatMark(record *Record) ([]byte, error) {
@@ -666,6 +729,9 @@ func (f *KeyValueFormatter) formatMark(record *Record) ([]byte, error) {
 	} else {
 		output.WriteString(fmt.Sprintf("%s\n", separator))
 		output.WriteString(fmt.Sprintf(" MARKED @ %s ", record.Time.Format(f.TimeFormat)))
+		if record.Message != "" {
+			output.WriteString(record.Message)
+		}
 	}
 
 	if !record.Attributes.IsEmpty() {
@@ -685,7 +751,7 @@ func (f *KeyValueFormatter) formatMark(record *Record) ([]byte, error) {
 		output.WriteString(fmt.Sprintf("%s\n", separator))
 	}
 
-	return []byte(output.String()), nil
+	return []byte(indentLines(output.String(), record.sectionDepth)), nil
 }
 
 func (f *KeyValueFormatter) writeKeyValueAttributes(output *strings.Builder, attrs *FlatAttributes) {
@@ -9,6 +9,7 @@ import (
 // HandlerOptions configures handler behavior using the functional options pattern
 type HandlerOptions struct {
 	level         Level
+	levelVar      *LevelVar
 	destination   Destination
 	sawmillOpts   *SawmillOptions
 	attributesKey string
@@ -20,6 +21,9 @@ type HandlerOptions struct {
 	includeLevel  bool
 	colorOutput   bool
 	attrFormat    string
+	extractors    []ContextExtractor
+	errorHandler  ErrorHandler
+	filter        *Filter
 }
 
 // HandlerOption is a function that configures HandlerOptions
@@ -113,6 +117,13 @@ func WithSourceInfo(enabled bool) HandlerOption {
 	}
 }
 
+// WithLevelVar sets a runtime-adjustable minimum level, taking precedence over WithLevel
+func WithLevelVar(levelVar *LevelVar) HandlerOption {
+	return func(opts *HandlerOptions) {
+		opts.levelVar = levelVar
+	}
+}
+
 // WithLevelInfo enables or disables log level in output
 func WithLevelInfo(enabled bool) HandlerOption {
 	return func(opts *HandlerOptions) {
@@ -154,3 +165,24 @@ func WithStderr() HandlerOption {
 		opts.destination = NewWriterDestination(os.Stderr)
 	}
 }
+
+// WithContextExtractor registers extractors that copy data from record.Context during Handle
+func WithContextExtractor(extractors ...ContextExtractor) HandlerOption {
+	return func(opts *HandlerOptions) {
+		opts.extractors = append(opts.extractors, extractors...)
+	}
+}
+
+// WithErrorHandler sets a handler for format and write errors; handled errors are not returned from Handle
+func WithErrorHandler(fn ErrorHandler) HandlerOption {
+	return func(opts *HandlerOptions) {
+		opts.errorHandler = fn
+	}
+}
+
+// WithFilter sets a filter expression that records must match to be written
+func WithFilter(filter *Filter) HandlerOption {
+	return func(opts *HandlerOptions) {
+		opts.filter = filter
+	}
+}
@@ -2,21 +2,27 @@ package sawmill
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"sync"
+	"sync/atomic"
 )
 
 // BaseHandler provides common functionality for all handlers
 type BaseHandler struct {
-	formatter Formatter
-	buffer    Buffer
-	level     Level
-	attrs     *FlatAttributes
-	groups    []string
-	mu        sync.RWMutex
+	formatter  Formatter
+	buffer     Buffer
+	level      Leveler
+	attrs      *FlatAttributes
+	groups     []string
+	extractors []ContextExtractor
+	filter     *Filter
+	onError    ErrorHandler
+	failures   *atomic.Uint64
+	mu         sync.RWMutex
 }
 
 // GetBuffer implements BufferProvider for BaseHandler.
@@ -24,28 +30,86 @@ func (h *BaseHandler) GetBuffer() Buffer {
 	return h.buffer
 }
 
-// NewBaseHandler creates a new base handler
-func NewBaseHandler(formatter Formatter, buffer Buffer, level Level) *BaseHandler {
+// NewBaseHandler creates a new base handler. The level may be a fixed Level
+// or a *LevelVar shared with other handlers and changed at runtime.
+func NewBaseHandler(formatter Formatter, buffer Buffer, level Leveler) *BaseHandler {
+	if level == nil {
+		level = LevelInfo
+	}
 	return &BaseHandler{
 		formatter: formatter,
 		buffer:    buffer,
 		level:     level,
 		attrs:     NewFlatAttributes(),
 		groups:    make([]string, 0),
+		failures:  &atomic.Uint64{},
 	}
 }
 
+// Flush implements Flusher by flushing the handler's buffer
+func (h *BaseHandler) Flush() error {
+	if h.buffer == nil {
+		return nil
+	}
+	return h.buffer.Flush()
+}
+
+// Close implements Closer by closing the handler's buffer, which flushes it first
+func (h *BaseHandler) Close() error {
+	if h.buffer == nil {
+		return nil
+	}
+	return h.buffer.Close()
+}
+
+// RegisterContextExtractor adds an extractor that runs against record.Context during Handle
+func (h *BaseHandler) RegisterContextExtractor(extractor ContextExtractor) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.extractors = append(h.extractors, extractor)
+}
+
+// FailedWrites returns the number of records this handler failed to format or write
+func (h *BaseHandler) FailedWrites() uint64 {
+	return h.failures.Load()
+}
+
 func (h *BaseHandler) Handle(ctx context.Context, record *Record) error {
-	if !h.Enabled(ctx, record.Level) {
+	return h.handleWith(ctx, record, h.formatter)
+}
+
+// handleWith runs the level check, filter, handler attributes and context
+// extractors of the handler and formats the record with formatter
+func (h *BaseHandler) handleWith(ctx context.Context, record *Record, formatter Formatter) error {
+	if !record.levelOverride && !h.Enabled(ctx, record.Level) {
+		return nil
+	}
+	if h.filter != nil && !h.filter.Match(record) {
 		return nil
 	}
 
+	err := h.handle(ctx, record, formatter)
+	if err != nil {
+		h.failures.Add(1)
+
+		// Errors are consumed when the handler has its own error handler
+		if h.onError != nil {
+			reportError(h.onError, err, record)
+			return nil
+		}
+	}
+	return err
+}
+
+func (h *BaseHandler) handle(ctx context.Context, record *Record, formatter Formatter) error {
+	record.ResolveValuers()
+
 	h.mu.RLock()
 
-	// Fast path: if no handler attributes, format directly without cloning
-	if h.attrs.IsEmpty() {
+	// Fast path: if no handler attributes or extractors, format directly without cloning
+	if h.attrs.IsEmpty() && len(h.extractors) == 0 {
 		h.mu.RUnlock()
-		data, err := h.formatter.Format(record)
+		data, err := formatter.Format(record)
 		if err != nil {
 			return err
 		}
@@ -54,22 +118,28 @@ func (h *BaseHandler) Handle(ctx context.Context, record *Record) error {
 	}
 
 	// Slow path: clone and merge when handler has attributes
-	recordCopy := &Record{
-		Time:       record.Time,
-		Level:      record.Level,
-		Message:    record.Message,
-		Attributes: record.Attributes.Clone(),
-		Context:    record.Context,
-		PC:         record.PC,
-	}
+	recordCopy := record.Clone()
 
 	// Add handler attributes
 	recordCopy.Attributes.Merge(h.attrs)
 
+	// Run context extractors
+	if len(h.extractors) > 0 {
+		extractCtx := record.Context
+		if extractCtx == nil {
+			extractCtx = ctx
+		}
+		if extractCtx != nil {
+			for _, extractor := range h.extractors {
+				extractor(extractCtx, recordCopy)
+			}
+		}
+	}
+
 	h.mu.RUnlock()
 
 	// Format the record
-	data, err := h.formatter.Format(recordCopy)
+	data, err := formatter.Format(recordCopy)
 	if err != nil {
 		return err
 	}
@@ -83,20 +153,10 @@ func (h *BaseHandler) WithAttrs(attrs []slog.Attr) Handler {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	newHandler := &BaseHandler{
-		formatter: h.formatter,
-		buffer:    h.buffer,
-		level:     h.level,
-		attrs:     h.attrs.Clone(),
-		groups:    make([]string, len(h.groups)),
-	}
-	copy(newHandler.groups, h.groups)
+	newHandler := h.clone()
 
 	for _, attr := range attrs {
-		keyPath := make([]string, len(h.groups))
-		copy(keyPath, h.groups)
-		keyPath = append(keyPath, attr.Key)
-		newHandler.attrs.Set(keyPath, attr.Value.Any())
+		newHandler.attrs.SetAttr(h.groups, attr)
 	}
 
 	return newHandler
@@ -106,23 +166,35 @@ func (h *BaseHandler) WithGroup(name string) Handler {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	newGroups := make([]string, len(h.groups)+1)
+	newHandler := h.clone()
+	newHandler.groups = append(newHandler.groups, name)
+
+	return newHandler
+}
+
+// clone copies the handler configuration; callers must hold the lock
+func (h *BaseHandler) clone() *BaseHandler {
+	newGroups := make([]string, len(h.groups))
 	copy(newGroups, h.groups)
-	newGroups[len(h.groups)] = name
+
+	newExtractors := make([]ContextExtractor, len(h.extractors))
+	copy(newExtractors, h.extractors)
 
 	return &BaseHandler{
-		formatter: h.formatter,
-		buffer:    h.buffer,
-		level:     h.level,
-		attrs:     h.attrs.Clone(),
-		groups:    newGroups,
+		formatter:  h.formatter,
+		buffer:     h.buffer,
+		level:      h.level,
+		attrs:      h.attrs.Clone(),
+		groups:     newGroups,
+		extractors: newExtractors,
+		filter:     h.filter,
+		onError:    h.onError,
+		failures:   h.failures,
 	}
 }
 
 func (h *BaseHandler) Enabled(ctx context.Context, level Level) bool {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-	return level >= h.level
+	return level >= h.level.Level()
 }
 
 // NeedsSource indicates if this handler needs source information
@@ -147,6 +219,34 @@ func (h *BaseHandler) NeedsSource() bool {
 	}
 }
 
+// handlerRetainsRecords reports whether a handler tree keeps records after Handle returns
+func handlerRetainsRecords(handler Handler) bool {
+	switch h := handler.(type) {
+	case RecordRetainer:
+		return h.RetainsRecords()
+	case interface{ Unwrap() Handler }:
+		return handlerRetainsRecords(h.Unwrap())
+	default:
+		return false
+	}
+}
+
+// handlerNeedsSource reports whether a handler needs the caller's program counter
+func handlerNeedsSource(handler Handler) bool {
+	// Check if handler implements SourceHandler interface
+	if sh, ok := handler.(SourceHandler); ok {
+		return sh.NeedsSource()
+	}
+
+	// Check if handler has NeedsSource method (BaseHandler)
+	if bh, ok := handler.(*BaseHandler); ok {
+		return bh.NeedsSource()
+	}
+
+	// Safe default - capture source info
+	return true
+}
+
 // TextHandler implements Handler for text output
 type TextHandler struct {
 	*BaseHandler
@@ -156,12 +256,8 @@ type TextHandler struct {
 func NewTextHandler(options ...HandlerOption) *TextHandler {
 	opts := NewHandlerOptions(options...)
 
-	buffer := createBuffer(opts)
-	level := determineLevel(opts)
-	formatter := createTextFormatter(opts)
-
 	return &TextHandler{
-		BaseHandler: NewBaseHandler(formatter, buffer, level),
+		BaseHandler: newBaseHandlerFromOptions(createTextFormatter(opts), opts),
 	}
 }
 
@@ -179,12 +275,8 @@ type JSONHandler struct {
 func NewJSONHandler(options ...HandlerOption) *JSONHandler {
 	opts := NewHandlerOptions(options...)
 
-	buffer := createBuffer(opts)
-	level := determineLevel(opts)
-	formatter := createJSONFormatter(opts)
-
 	return &JSONHandler{
-		BaseHandler: NewBaseHandler(formatter, buffer, level),
+		BaseHandler: newBaseHandlerFromOptions(createJSONFormatter(opts), opts),
 	}
 }
 
@@ -202,12 +294,8 @@ type XMLHandler struct {
 func NewXMLHandler(options ...HandlerOption) *XMLHandler {
 	opts := NewHandlerOptions(options...)
 
-	buffer := createBuffer(opts)
-	level := determineLevel(opts)
-	formatter := createXMLFormatter(opts)
-
 	return &XMLHandler{
-		BaseHandler: NewBaseHandler(formatter, buffer, level),
+		BaseHandler: newBaseHandlerFromOptions(createXMLFormatter(opts), opts),
 	}
 }
 
@@ -225,12 +313,8 @@ type YAMLHandler struct {
 func NewYAMLHandler(options ...HandlerOption) *YAMLHandler {
 	opts := NewHandlerOptions(options...)
 
-	buffer := createBuffer(opts)
-	level := determineLevel(opts)
-	formatter := createYAMLFormatter(opts)
-
 	return &YAMLHandler{
-		BaseHandler: NewBaseHandler(formatter, buffer, level),
+		BaseHandler: newBaseHandlerFromOptions(createYAMLFormatter(opts), opts),
 	}
 }
 
@@ -248,12 +332,8 @@ type KeyValueHandler struct {
 func NewKeyValueHandler(options ...HandlerOption) *KeyValueHandler {
 	opts := NewHandlerOptions(options...)
 
-	buffer := createBuffer(opts)
-	level := determineLevel(opts)
-	formatter := createKeyValueFormatter(opts)
-
 	return &KeyValueHandler{
-		BaseHandler: NewBaseHandler(formatter, buffer, level),
+		BaseHandler: newBaseHandlerFromOptions(createKeyValueFormatter(opts), opts),
 	}
 }
 
@@ -275,17 +355,58 @@ func NewMultiHandler(handlers ...Handler) *MultiHandler {
 	}
 }
 
+// Handlers returns the child handlers
+func (h *MultiHandler) Handlers() []Handler {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	handlers := make([]Handler, len(h.handlers))
+	copy(handlers, h.handlers)
+	return handlers
+}
+
 func (h *MultiHandler) Handle(ctx context.Context, record *Record) error {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	var lastErr error
+	var errs []error
 	for _, handler := range h.handlers {
 		if err := handler.Handle(ctx, record); err != nil {
-			lastErr = err
+			errs = append(errs, err)
 		}
 	}
-	return lastErr
+	return errors.Join(errs...)
+}
+
+// RetainsRecords implements RecordRetainer by checking every child handler
+func (h *MultiHandler) RetainsRecords() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, handler := range h.handlers {
+		if handlerRetainsRecords(handler) {
+			return true
+		}
+	}
+	return false
+}
+
+// Flush implements Flusher by flushing every child handler
+func (h *MultiHandler) Flush() error {
+	var errs []error
+	for _, handler := range h.Handlers() {
+		errs = append(errs, flushHandler(handler))
+	}
+	return errors.Join(errs...)
+}
+
+// Close implements Closer by closing every child handler
+func (h *MultiHandler) Close() error {
+	var errs []error
+	for _, handler := range h.Handlers() {
+		errs = append(errs, closeHandler(handler))
+	}
+	return errors.Join(errs...)
 }
 
 func (h *MultiHandler) WithAttrs(attrs []slog.Attr) Handler {
@@ -347,26 +468,11 @@ func getDestinationBuffer(dest Destination) Buffer {
 }
 
 func parseLevel(levelStr string) Level {
-	switch levelStr {
-	case "trace":
-		return LevelTrace
-	case "debug":
-		return LevelDebug
-	case "info":
-		return LevelInfo
-	case "warn":
-		return LevelWarn
-	case "error":
-		return LevelError
-	case "fatal":
-		return LevelFatal
-	case "panic":
-		return LevelPanic
-	case "mark":
-		return LevelMark
-	default:
+	level, err := ParseLevel(levelStr)
+	if err != nil {
 		return LevelInfo
 	}
+	return level
 }
 
 // NewWriterDestination creates a new writer destination
@@ -503,13 +609,45 @@ func createBuffer(options *HandlerOptions) Buffer {
 	return getDestinationBuffer(options.destination)
 }
 
-func determineLevel(options *HandlerOptions) Level {
+func newBaseHandlerFromOptions(formatter Formatter, options *HandlerOptions) *BaseHandler {
+	return newBaseHandlerWithBuffer(formatter, createBuffer(options), options)
+}
+
+func newBaseHandlerWithBuffer(formatter Formatter, buffer Buffer, options *HandlerOptions) *BaseHandler {
+	handler := NewBaseHandler(formatter, buffer, determineLevel(options))
+	handler.extractors = append(handler.extractors, options.extractors...)
+	handler.onError = options.errorHandler
+	handler.filter = determineFilter(options)
+	return handler
+}
+
+func determineLevel(options *HandlerOptions) Leveler {
+	if options.levelVar != nil {
+		return options.levelVar
+	}
 	if options.sawmillOpts != nil {
 		return parseLevel(options.sawmillOpts.LogLevel)
 	}
 	return options.level
 }
 
+func determineFilter(options *HandlerOptions) *Filter {
+	if options.filter != nil {
+		return options.filter
+	}
+	if options.sawmillOpts == nil || options.sawmillOpts.Filter == "" {
+		return nil
+	}
+
+	filter, err := CompileFilter(options.sawmillOpts.Filter)
+	if err != nil {
+		// Invalid filters are reported as configuration errors, not failed writes, and ignored
+		notifyError(options.errorHandler, fmt.Errorf("%w; writing records unfiltered", err), nil)
+		return nil
+	}
+	return filter
+}
+
 func createTextFormatter(options *HandlerOptions) *TextFormatter {
 	formatter := NewTextFormatter()
 	formatter.TimeFormat = options.timeFormat
@@ -584,6 +722,12 @@ type BufferProvider interface {
 	GetBuffer() Buffer
 }
 
+// formattingHandler is implemented by handlers built on BaseHandler, which can
+// handle a record with a formatter other than their own
+type formattingHandler interface {
+	handleWith(ctx context.Context, record *Record, formatter Formatter) error
+}
+
 // temporaryHandler wraps an existing handler to use a different formatter temporarily
 type temporaryHandler struct {
 	originalHandler Handler
@@ -591,10 +735,17 @@ type temporaryHandler struct {
 }
 
 func (h *temporaryHandler) Handle(ctx context.Context, record *Record) error {
-	if !h.originalHandler.Enabled(ctx, record.Level) {
+	// Base handlers apply their filter, attributes and context extractors as usual
+	if base, ok := h.originalHandler.(formattingHandler); ok {
+		return base.handleWith(ctx, record, h.formatter)
+	}
+
+	if !record.levelOverride && !h.originalHandler.Enabled(ctx, record.Level) {
 		return nil
 	}
 
+	record.ResolveValuers()
+
 	// Format with our temporary formatter
 	data, err := h.formatter.Format(record)
 	if err != nil {
@@ -632,3 +783,8 @@ func (h *temporaryHandler) WithGroup(name string) Handler {
 func (h *temporaryHandler) Enabled(ctx context.Context, level Level) bool {
 	return h.originalHandler.Enabled(ctx, level)
 }
+
+// Unwrap returns the wrapped handler
+func (h *temporaryHandler) Unwrap() Handler {
+	return h.originalHandler
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"log"
 	"log/slog"
+	"strings"
 	"time"
 )
 
@@ -31,6 +32,11 @@ type Record struct {
 	Context    context.Context
 	PC         uintptr
 	OutputID   string // Unique identifier for correlating multiline outputs
+	LoggerName string // Dotted name of the logger that created the record
+
+	levelOverride bool // Level let through by a LevelRegistry below the handler's level; handlers skip their level check
+	sectionDepth  int  // Nesting depth of the enclosing Section; text output is indented by it
+	correlated    bool // OutputID links the record to other written records; formatters write it
 }
 
 // NewRecord creates a new log record
@@ -44,15 +50,41 @@ func NewRecord(level Level, msg string) *Record {
 	}
 }
 
-// With adds nested attributes to the record
+// Clone returns a deep copy of the record, including its attributes, that is
+// independent of the record pool
+func (r *Record) Clone() *Record {
+	clone := *r
+	if r.Attributes != nil {
+		clone.Attributes = r.Attributes.Clone()
+	} else {
+		clone.Attributes = NewFlatAttributes()
+	}
+	return &clone
+}
+
+// correlatedOutputID returns the OutputID when it links the record to other
+// written records, such as replayed, timed or section records
+func (r *Record) correlatedOutputID() string {
+	if !r.correlated {
+		return ""
+	}
+	return r.OutputID
+}
+
+// With adds nested attributes to the record; errors, slog.Attr and
+// slog.Value values are expanded like logging arguments
 func (r *Record) With(keyPath []string, value interface{}) *Record {
-	r.Attributes.Set(keyPath, value)
+	if !r.Attributes.setSlogValue(strings.Join(keyPath, "."), value) {
+		r.Attributes.Set(keyPath, value)
+	}
 	return r
 }
 
 // WithDot adds attributes using dot notation
 func (r *Record) WithDot(dotPath string, value interface{}) *Record {
-	r.Attributes.SetByDotNotation(dotPath, value)
+	if !r.Attributes.setSlogValue(dotPath, value) {
+		r.Attributes.SetByDotNotation(dotPath, value)
+	}
 	return r
 }
 
@@ -74,7 +106,12 @@ type Buffer interface {
 	Reset()
 }
 
-// Handler defines the interface for log handling
+// Handler defines the interface for log handling.
+//
+// Records passed to Handle are owned by the caller and may be reset and reused
+// from the record pool as soon as Handle returns. A handler that keeps a record
+// afterwards must keep record.Clone(), or implement RecordRetainer so loggers
+// pass it records that are never returned to the pool.
 type Handler interface {
 	Handle(ctx context.Context, record *Record) error
 	WithAttrs(attrs []slog.Attr) Handler
@@ -82,15 +119,32 @@ type Handler interface {
 	Enabled(ctx context.Context, level Level) bool
 }
 
+// RecordRetainer is implemented by handlers that keep records after Handle
+// returns. Loggers hand such handlers records that are not returned to the pool.
+type RecordRetainer interface {
+	RetainsRecords() bool
+}
+
 // SourceHandler extends Handler to indicate if source info is needed
 type SourceHandler interface {
 	Handler
 	NeedsSource() bool
 }
 
+// Flusher is implemented by handlers that can write out buffered records
+type Flusher interface {
+	Flush() error
+}
+
+// Closer is implemented by handlers that hold buffers, files or other resources
+type Closer interface {
+	Close() error
+}
+
 // Logger represents the main logging interface
 type Logger interface {
 	Log(ctx context.Context, level Level, msg string, args ...interface{})
+	LogAttrs(ctx context.Context, level Level, msg string, attrs ...Attr)
 	LogRecord(ctx context.Context, record *Record)
 
 	Trace(msg string, args ...interface{})
@@ -102,10 +156,33 @@ type Logger interface {
 	Panic(msg string, args ...interface{})
 	Mark(msg string, args ...interface{})
 
+	TraceContext(ctx context.Context, msg string, args ...interface{})
+	DebugContext(ctx context.Context, msg string, args ...interface{})
+	InfoContext(ctx context.Context, msg string, args ...interface{})
+	WarnContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+	FatalContext(ctx context.Context, msg string, args ...interface{})
+	PanicContext(ctx context.Context, msg string, args ...interface{})
+	MarkContext(ctx context.Context, msg string, args ...interface{})
+
+	Start(operation string, args ...interface{}) func(err error)
+	Section(name string, args ...interface{}) *Section
+	Recover(opts *RecoverOptions)
+	Flush() error
+	Shutdown(ctx context.Context) error
+
 	WithNested(keyPath []string, value interface{}) Logger
 	WithDot(dotPath string, value interface{}) Logger
 	WithGroup(name string) Logger
+	Named(name string) Logger
+	WithLevelRegistry(registry *LevelRegistry) Logger
 	WithCallback(fn CallbackFunc) Logger
+	WithErrorHandler(fn ErrorHandler) Logger
+	WithErrorStack(level Level) Logger
+	WithCallerSkip(skip int) Logger
+	WithStrictArgs(enabled bool) Logger
+	WithTimerOptions(opts *TimerOptions) Logger
+	WithHandler(handler Handler) Logger
 	SetHandler(handler Handler)
 	Handler() Handler
 	As(formatter Formatter) AsLogger
@@ -122,6 +199,16 @@ type AsLogger interface {
 	Fatal(msg string, args ...interface{})
 	Panic(msg string, args ...interface{})
 	Mark(msg string, args ...interface{})
+
+	TraceContext(ctx context.Context, msg string, args ...interface{})
+	DebugContext(ctx context.Context, msg string, args ...interface{})
+	InfoContext(ctx context.Context, msg string, args ...interface{})
+	WarnContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+	FatalContext(ctx context.Context, msg string, args ...interface{})
+	PanicContext(ctx context.Context, msg string, args ...interface{})
+	MarkContext(ctx context.Context, msg string, args ...interface{})
+
 	Log(ctx context.Context, level Level, msg string, args ...interface{})
 }
 
@@ -0,0 +1,113 @@
+package sawmill
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Leveler provides a minimum level; Level and *LevelVar implement it
+type Leveler interface {
+	Level() Level
+}
+
+// Level returns the level itself so a Level can be used as a Leveler
+func (l Level) Level() Level {
+	return l
+}
+
+// String returns the upper-case name of the level
+func (l Level) String() string {
+	return levelToString(l)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseLevel
+func (l *Level) UnmarshalText(data []byte) error {
+	level, err := ParseLevel(string(data))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// levelOr returns fallback for the zero Level, which is not a defined level,
+// so options left unset use their defaults
+func levelOr(level, fallback Level) Level {
+	if level == 0 {
+		return fallback
+	}
+	return level
+}
+
+// ParseLevel converts a case-insensitive level name such as "debug" or "WARN" to a Level
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "trace":
+		return LevelTrace, nil
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	case "fatal":
+		return LevelFatal, nil
+	case "panic":
+		return LevelPanic, nil
+	case "mark":
+		return LevelMark, nil
+	default:
+		return LevelInfo, fmt.Errorf("sawmill: unknown level %q", s)
+	}
+}
+
+// LevelVar is a Level that can be changed at runtime and shared by many handlers.
+// Reads and writes are atomic. The zero value is LevelInfo.
+type LevelVar struct {
+	offset atomic.Int64 // distance from LevelInfo so the zero value is LevelInfo
+}
+
+// NewLevelVar creates a LevelVar set to level
+func NewLevelVar(level Level) *LevelVar {
+	v := &LevelVar{}
+	v.Set(level)
+	return v
+}
+
+// Level returns the current level
+func (v *LevelVar) Level() Level {
+	return Level(v.offset.Load()) + LevelInfo
+}
+
+// Set changes the level
+func (v *LevelVar) Set(level Level) {
+	v.offset.Store(int64(level - LevelInfo))
+}
+
+// String returns a description of the LevelVar and its current level
+func (v *LevelVar) String() string {
+	return fmt.Sprintf("LevelVar(%s)", v.Level())
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (v *LevelVar) MarshalText() ([]byte, error) {
+	return v.Level().MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseLevel
+func (v *LevelVar) UnmarshalText(data []byte) error {
+	level, err := ParseLevel(string(data))
+	if err != nil {
+		return err
+	}
+	v.Set(level)
+	return nil
+}
@@ -0,0 +1,185 @@
+package sawmill
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// LevelRegistry maps logger name patterns to minimum levels. Loggers attached
+// with WithLevelRegistry consult it before records reach their handler.
+//
+// Patterns are exact names such as "billing.invoices", prefixes such as
+// "billing.*" that match "billing" and every logger below it, or "*" for all
+// loggers. The most specific matching pattern wins; names without a match
+// fall back to the handler's level. The registry is safe to change at runtime.
+type LevelRegistry struct {
+	rules atomic.Pointer[[]levelRule]
+}
+
+// levelRule is a single pattern=level entry
+type levelRule struct {
+	pattern string
+	prefix  string // name prefix for wildcard patterns
+	exact   bool
+	level   Level
+}
+
+// NewLevelRegistry creates a registry from a spec such as "billing.*=debug,*=info"
+func NewLevelRegistry(spec string) (*LevelRegistry, error) {
+	registry := &LevelRegistry{}
+	if err := registry.Set(spec); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// Set replaces every rule with the rules in spec
+func (r *LevelRegistry) Set(spec string) error {
+	rules, err := parseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+	r.rules.Store(&rules)
+	return nil
+}
+
+// SetLevel adds or replaces the rule for a single pattern
+func (r *LevelRegistry) SetLevel(pattern string, level Level) {
+	rule := newLevelRule(strings.TrimSpace(pattern), level)
+
+	for {
+		current := r.rules.Load()
+
+		var rules []levelRule
+		if current != nil {
+			rules = make([]levelRule, 0, len(*current)+1)
+			for _, existing := range *current {
+				if existing.pattern != rule.pattern {
+					rules = append(rules, existing)
+				}
+			}
+		}
+		rules = append(rules, rule)
+
+		if r.rules.CompareAndSwap(current, &rules) {
+			return
+		}
+	}
+}
+
+// Level returns the minimum level for a logger name and whether any rule matched
+func (r *LevelRegistry) Level(name string) (Level, bool) {
+	rules := r.rules.Load()
+	if rules == nil {
+		return LevelInfo, false
+	}
+
+	var match *levelRule
+	for i := range *rules {
+		rule := &(*rules)[i]
+		if !rule.matches(name) {
+			continue
+		}
+		if match == nil || rule.specificity() > match.specificity() {
+			match = rule
+		}
+	}
+
+	if match == nil {
+		return LevelInfo, false
+	}
+	return match.level, true
+}
+
+// String returns the rules as a spec accepted by Set, sorted by pattern
+func (r *LevelRegistry) String() string {
+	rules := r.rules.Load()
+	if rules == nil {
+		return ""
+	}
+
+	entries := make([]string, 0, len(*rules))
+	for _, rule := range *rules {
+		entries = append(entries, rule.pattern+"="+strings.ToLower(rule.level.String()))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (r *LevelRegistry) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using Set
+func (r *LevelRegistry) UnmarshalText(data []byte) error {
+	return r.Set(string(data))
+}
+
+// parseLevelSpec parses comma-separated pattern=level entries
+func parseLevelSpec(spec string) ([]levelRule, error) {
+	var rules []levelRule
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		pattern, levelName, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("sawmill: invalid level rule %q: expected pattern=level", entry)
+		}
+
+		level, err := ParseLevel(levelName)
+		if err != nil {
+			return nil, err
+		}
+
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			return nil, fmt.Errorf("sawmill: invalid level rule %q: empty pattern", entry)
+		}
+		rules = append(rules, newLevelRule(pattern, level))
+	}
+	return rules, nil
+}
+
+// newLevelRule builds a rule for a pattern
+func newLevelRule(pattern string, level Level) levelRule {
+	rule := levelRule{pattern: pattern, level: level}
+	switch {
+	case pattern == "*":
+		rule.prefix = ""
+	case strings.HasSuffix(pattern, ".*"):
+		rule.prefix = strings.TrimSuffix(pattern, ".*")
+	default:
+		rule.exact = true
+		rule.prefix = pattern
+	}
+	return rule
+}
+
+// matches reports whether the rule applies to a logger name
+func (r *levelRule) matches(name string) bool {
+	if r.exact {
+		return name == r.prefix
+	}
+	if r.prefix == "" {
+		return true
+	}
+	return name == r.prefix || strings.HasPrefix(name, r.prefix+".")
+}
+
+// specificity ranks rules so that longer prefixes win and exact names beat wildcards
+func (r *levelRule) specificity() int {
+	if r.pattern == "*" {
+		return 0
+	}
+	score := 2 * (len(r.prefix) + 1)
+	if r.exact {
+		score++
+	}
+	return score
+}
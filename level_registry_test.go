@@ -0,0 +1,143 @@
+package sawmill
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLevelRegistryMatching(t *testing.T) {
+	registry, err := NewLevelRegistry("billing.*=debug, billing.invoices=error, *=warn, payments.stripe=trace")
+	if err != nil {
+		t.Fatalf("NewLevelRegistry failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		expected Level
+	}{
+		{"", LevelWarn},
+		{"api", LevelWarn},
+		{"billing", LevelDebug},
+		{"billing.refunds", LevelDebug},
+		{"billing.invoices", LevelError},
+		{"billing.invoices.pdf", LevelDebug},
+		{"billingsvc", LevelWarn},
+		{"payments.stripe", LevelTrace},
+	}
+
+	for _, test := range tests {
+		level, ok := registry.Level(test.name)
+		if !ok || level != test.expected {
+			t.Errorf("Level(%q) = %v, %v; want %v", test.name, level, ok, test.expected)
+		}
+	}
+
+	if got := registry.String(); got != "*=warn,billing.*=debug,billing.invoices=error,payments.stripe=trace" {
+		t.Errorf("Unexpected String(): %s", got)
+	}
+}
+
+func TestLevelRegistryInvalidSpec(t *testing.T) {
+	for _, spec := range []string{"billing", "billing=loud", "=debug"} {
+		if _, err := NewLevelRegistry(spec); err == nil {
+			t.Errorf("Expected error for spec %q", spec)
+		}
+	}
+
+	registry, _ := NewLevelRegistry("*=info")
+	if err := registry.Set("broken"); err == nil {
+		t.Error("Expected Set to reject invalid spec")
+	}
+	if level, ok := registry.Level("any"); !ok || level != LevelInfo {
+		t.Errorf("Expected rules to be unchanged after failed Set, got %v, %v", level, ok)
+	}
+}
+
+func TestNamedLoggerWithLevelRegistry(t *testing.T) {
+	buf := &bytes.Buffer{}
+	registry, _ := NewLevelRegistry("billing.*=debug")
+
+	root := New(NewTextHandler(WithWriter(buf), WithLevel(LevelInfo))).WithLevelRegistry(registry)
+	invoices := root.Named("billing").Named("invoices")
+	api := root.Named("api")
+
+	invoices.Debug("Invoice debug")
+	api.Debug("API debug")
+	api.Info("API info")
+
+	output := buf.String()
+	if !strings.Contains(output, "billing.invoices: Invoice debug") {
+		t.Errorf("Expected billing debug to bypass the handler level: %s", output)
+	}
+	if strings.Contains(output, "API debug") {
+		t.Errorf("Expected unmatched logger to use the handler level: %s", output)
+	}
+	if !strings.Contains(output, "api: API info") {
+		t.Errorf("Expected api info output: %s", output)
+	}
+
+	buf.Reset()
+	registry.SetLevel("billing.invoices", LevelError)
+	invoices.Warn("Filtered warning")
+	invoices.As(NewJSONFormatter()).Debug("Filtered debug")
+	root.Named("billing").Debug("Billing debug")
+
+	output = buf.String()
+	if strings.Contains(output, "Filtered") {
+		t.Errorf("Expected runtime rule change to filter invoices: %s", output)
+	}
+	if !strings.Contains(output, "Billing debug") {
+		t.Errorf("Expected billing prefix rule to still apply: %s", output)
+	}
+}
+
+func TestLevelRegistryKeepsChildHandlerLevels(t *testing.T) {
+	infoBuf, errorBuf := &bytes.Buffer{}, &bytes.Buffer{}
+	registry, _ := NewLevelRegistry("*=info")
+
+	logger := New(NewMultiHandler(
+		NewTextHandler(WithWriter(infoBuf), WithLevel(LevelInfo)),
+		NewTextHandler(WithWriter(errorBuf), WithLevel(LevelError)),
+	)).WithLevelRegistry(registry).Named("svc")
+
+	logger.Info("hello")
+	logger.Error("failed")
+
+	if !strings.Contains(infoBuf.String(), "svc: hello") {
+		t.Errorf("Expected the info sink to write the record: %s", infoBuf.String())
+	}
+	if strings.Contains(errorBuf.String(), "hello") || !strings.Contains(errorBuf.String(), "svc: failed") {
+		t.Errorf("Expected the error-only sink to keep its level: %s", errorBuf.String())
+	}
+}
+
+func TestLoggerNameInFormatters(t *testing.T) {
+	tests := []struct {
+		name      string
+		formatter Formatter
+		expected  string
+	}{
+		{"json", NewJSONFormatter(), `"logger":"billing.invoices"`},
+		{"text", NewTextFormatter(), " billing.invoices: Named message"},
+		{"keyvalue", NewKeyValueFormatter(), " logger=billing.invoices"},
+		{"xml", NewXMLFormatter(), "<logger>billing.invoices</logger>"},
+		{"yaml", NewYAMLFormatter(), "logger: billing.invoices\n"},
+	}
+
+	for _, test := range tests {
+		buffer := NewMemoryBuffer(0)
+		logger := New(NewBaseHandler(test.formatter, buffer, LevelInfo)).Named("billing.invoices")
+		logger.Info("Named message")
+
+		if output := string(buffer.Bytes()); !strings.Contains(output, test.expected) {
+			t.Errorf("%s: expected %q in output: %s", test.name, test.expected, output)
+		}
+	}
+
+	buffer := NewMemoryBuffer(0)
+	New(NewBaseHandler(NewJSONFormatter(), buffer, LevelInfo)).Info("Unnamed")
+	if strings.Contains(string(buffer.Bytes()), "logger") {
+		t.Errorf("Expected no logger field for unnamed loggers: %s", buffer.Bytes())
+	}
+}
@@ -0,0 +1,115 @@
+package sawmill
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParseLevelNames(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Level
+	}{
+		{"trace", LevelTrace},
+		{"DEBUG", LevelDebug},
+		{" info ", LevelInfo},
+		{"warning", LevelWarn},
+		{"Error", LevelError},
+		{"fatal", LevelFatal},
+		{"panic", LevelPanic},
+		{"mark", LevelMark},
+	}
+
+	for _, test := range tests {
+		level, err := ParseLevel(test.input)
+		if err != nil || level != test.expected {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", test.input, level, err, test.expected)
+		}
+	}
+
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+}
+
+func TestLevelVar(t *testing.T) {
+	var zero LevelVar
+	if zero.Level() != LevelInfo {
+		t.Errorf("Expected zero LevelVar to be INFO, got %v", zero.Level())
+	}
+
+	levelVar := NewLevelVar(LevelWarn)
+	buf1, buf2 := &bytes.Buffer{}, &bytes.Buffer{}
+	logger1 := New(NewTextHandler(WithWriter(buf1), WithLevelVar(levelVar)))
+	logger2 := New(NewJSONHandler(WithWriter(buf2), WithLevelVar(levelVar)).WithGroup("app"))
+
+	logger1.Info("Hidden")
+	logger2.Info("Hidden")
+	if buf1.Len() != 0 || buf2.Len() != 0 {
+		t.Fatalf("Expected INFO to be filtered at WARN: %q %q", buf1.String(), buf2.String())
+	}
+
+	levelVar.Set(LevelDebug)
+	logger1.Debug("Visible")
+	logger2.Debug("Visible")
+	if !strings.Contains(buf1.String(), "Visible") || !strings.Contains(buf2.String(), "Visible") {
+		t.Errorf("Expected level change to apply to every handler: %q %q", buf1.String(), buf2.String())
+	}
+
+	if levelVar.String() != "LevelVar(DEBUG)" {
+		t.Errorf("Unexpected String(): %s", levelVar.String())
+	}
+}
+
+func TestLevelVarText(t *testing.T) {
+	var config struct {
+		Level *LevelVar `json:"level"`
+	}
+	config.Level = &LevelVar{}
+
+	if err := json.Unmarshal([]byte(`{"level":"error"}`), &config); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if config.Level.Level() != LevelError {
+		t.Errorf("Expected ERROR, got %v", config.Level.Level())
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil || string(data) != `{"level":"ERROR"}` {
+		t.Errorf("Marshal = %s, %v", data, err)
+	}
+
+	if err := config.Level.UnmarshalText([]byte("loud")); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+	if config.Level.Level() != LevelError {
+		t.Errorf("Expected level to be unchanged after error, got %v", config.Level.Level())
+	}
+}
+
+func TestLevelVarConcurrentAccess(t *testing.T) {
+	levelVar := NewLevelVar(LevelInfo)
+	handler := NewBaseHandler(NewTextFormatter(), NewMemoryBuffer(0), levelVar)
+	logger := New(handler)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Debug("message")
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				levelVar.Set(Level(int(LevelDebug) + (i+j)%2))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -4,11 +4,9 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
-	"fmt"
 	"log"
 	"log/slog"
 	"reflect"
-	"runtime"
 	"strings"
 	"sync"
 )
@@ -19,6 +17,14 @@ type logger struct {
 	attrs     *FlatAttributes
 	groups    []string
 	callbacks []CallbackFunc
+	onError   ErrorHandler
+	name      string
+	levels    *LevelRegistry
+	errStack  *Level
+	skip      int
+	strict    bool
+	timer     *TimerOptions
+	section   *sectionState
 	mu        sync.RWMutex
 }
 
@@ -39,22 +45,54 @@ func Default() Logger {
 
 // Log logs a message at the specified level with optional arguments
 func (l *logger) Log(ctx context.Context, level Level, msg string, args ...interface{}) {
-	if !l.handler.Enabled(ctx, level) {
+	if !l.enabled(ctx, level) {
 		return
 	}
 
-	record := NewRecordFromPool(level, msg)
-	record.Context = ctx
+	// Only capture frame if the handler/formatter might need it
+	var pc uintptr
+	if l.needsSourceCapture() {
+		pc = callerPC(l.skip)
+	}
+
+	record := l.newRecord(ctx, level, msg, pc, args)
+	l.dispatch(ctx, l.handler, record)
+}
+
+// LogAttrs logs a message with typed attributes, avoiding the boxing of
+// key/value arguments
+func (l *logger) LogAttrs(ctx context.Context, level Level, msg string, attrs ...Attr) {
+	if !l.enabled(ctx, level) {
+		return
+	}
 
 	// Only capture frame if the handler/formatter might need it
+	var pc uintptr
 	if l.needsSourceCapture() {
-		var pcs [1]uintptr
-		runtime.Callers(3, pcs[:])
-		record.PC = pcs[0]
+		pc = callerPC(l.skip)
 	}
 
+	record := l.newRecord(ctx, level, msg, pc, nil)
+	for _, attr := range attrs {
+		l.setAttr(record, attr)
+	}
+	l.dispatch(ctx, l.handler, record)
+}
+
+// newRecord builds a pooled record carrying the logger's attributes, the
+// call arguments and the result of the registered callbacks
+func (l *logger) newRecord(ctx context.Context, level Level, msg string, pc uintptr, args []interface{}) *Record {
+	record := NewRecordFromPool(level, msg)
+	record.Context = ctx
+	record.PC = pc
+	record.LoggerName = l.name
+	record.levelOverride = l.overridesLevel(ctx, record.Level)
+
 	record.Attributes.Merge(l.attrs)
-	l.processArgsOptimized(record, args...)
+	l.enterSection(record)
+	if err := l.processArgs(record, args...); err != nil && l.strict {
+		l.reportArgsError(err, record)
+	}
 
 	l.mu.RLock()
 	for _, callback := range l.callbacks {
@@ -62,38 +100,94 @@ func (l *logger) Log(ctx context.Context, level Level, msg string, args ...inter
 	}
 	l.mu.RUnlock()
 
-	err := l.handler.Handle(ctx, record)
+	return record
+}
+
+// dispatch passes a record to handler, reports any error and returns the
+// record to the pool unless handler retains records
+func (l *logger) dispatch(ctx context.Context, handler Handler, record *Record) {
+	if err := handler.Handle(ctx, record); err != nil {
+		l.handleError(err, record)
+	}
 
 	// Return record to pool after use
-	ReturnRecordToPool(record)
+	if !handlerRetainsRecords(handler) {
+		ReturnRecordToPool(record)
+	}
+}
 
-	if err != nil {
-		// Log error handling could be added here if needed
+// logCorrelated writes a record whose OutputID id links it to other records,
+// such as the start and completion of a timed operation. prepare, when set,
+// adjusts the record before it is dispatched.
+func (l *logger) logCorrelated(level Level, msg string, id string, args []interface{}, prepare func(record *Record)) {
+	ctx := context.Background()
+	if !l.enabled(ctx, level) {
+		return
 	}
+
+	// Only capture frame if the handler/formatter might need it
+	var pc uintptr
+	if l.needsSourceCapture() {
+		pc = callerPC(l.skip)
+	}
+
+	record := l.newRecord(ctx, level, msg, pc, args)
+	record.OutputID = id
+	record.correlated = true
+	if prepare != nil {
+		prepare(record)
+	}
+	l.dispatch(ctx, l.handler, record)
 }
 
-// needsSourceCapture checks if source capture is needed
-func (l *logger) needsSourceCapture() bool {
-	// Check if handler implements SourceHandler interface
-	if sh, ok := l.handler.(SourceHandler); ok {
-		return sh.NeedsSource()
+// registryLevel returns the level the level registry assigns to the logger's name
+func (l *logger) registryLevel() (Level, bool) {
+	if l.levels == nil {
+		return LevelInfo, false
 	}
+	return l.levels.Level(l.name)
+}
+
+// overridesLevel reports whether the level registry lets through a record at
+// level that the handler would reject, so handlers skip their level check
+func (l *logger) overridesLevel(ctx context.Context, level Level) bool {
+	_, ok := l.registryLevel()
+	return ok && !l.handler.Enabled(ctx, level)
+}
 
-	// Check if handler has NeedsSource method (BaseHandler)
-	if bh, ok := l.handler.(*BaseHandler); ok {
-		return bh.NeedsSource()
+// enabled checks the level registry first and falls back to the handler
+func (l *logger) enabled(ctx context.Context, level Level) bool {
+	if minLevel, ok := l.registryLevel(); ok {
+		return level >= minLevel
 	}
+	return l.handler.Enabled(ctx, level)
+}
 
-	// Safe default - capture source info
-	return true
+// handleError reports a handler error to the logger's error handler
+func (l *logger) handleError(err error, record *Record) {
+	l.mu.RLock()
+	onError := l.onError
+	l.mu.RUnlock()
+
+	reportError(onError, err, record)
+}
+
+// needsSourceCapture checks if source capture is needed
+func (l *logger) needsSourceCapture() bool {
+	return handlerNeedsSource(l.handler)
 }
 
 // LogRecord logs a pre-constructed record
 func (l *logger) LogRecord(ctx context.Context, record *Record) {
-	if !l.handler.Enabled(ctx, record.Level) {
+	if !l.enabled(ctx, record.Level) {
 		return
 	}
 
+	if record.LoggerName == "" {
+		record.LoggerName = l.name
+	}
+	record.levelOverride = l.overridesLevel(ctx, record.Level)
+
 	record.Attributes.Merge(l.attrs)
 
 	l.mu.RLock()
@@ -102,102 +196,111 @@ func (l *logger) LogRecord(ctx context.Context, record *Record) {
 	}
 	l.mu.RUnlock()
 
-	err := l.handler.Handle(ctx, record)
-
-	// Return record to pool after use
-	ReturnRecordToPool(record)
-
-	if err != nil {
-		// Log error handling could be added here if needed
-	}
+	l.dispatch(ctx, l.handler, record)
 }
 
-func (l *logger) processArgs(record *Record, args ...interface{}) {
-	for i := 0; i < len(args); i += 2 {
-		if i+1 >= len(args) {
-			break
+// processArgs stores key/value arguments following slog's rules: a string
+// key takes the next argument as its value, Attr and slog.Attr arguments
+// stand alone, and a key without a value or any other argument is stored
+// under !BADKEY. Several malformed arguments are stored together as a slice
+// in argument order. The returned error describes the first malformed argument.
+func (l *logger) processArgs(record *Record, args ...interface{}) error {
+	var malformed error
+	var bad []interface{}
+	for i := 0; i < len(args); {
+		if l.setAttrArg(record, args[i]) {
+			i++
+			continue
 		}
 
 		key, ok := args[i].(string)
-		if !ok {
+		if !ok || i+1 >= len(args) {
+			if malformed == nil {
+				malformed = malformedArgError(args, i)
+			}
+			bad = append(bad, args[i])
+			i++
 			continue
 		}
 
-		value := args[i+1]
-
-		keyPath := make([]string, len(l.groups))
-		copy(keyPath, l.groups)
-		keyPath = append(keyPath, key)
+		l.setArg(record, key, args[i+1])
+		i += 2
+	}
 
-		record.Attributes.Set(keyPath, value)
+	switch len(bad) {
+	case 0:
+	case 1:
+		record.Attributes.SetFast(l.groupKey(BadKey), bad[0])
+	default:
+		record.Attributes.SetFast(l.groupKey(BadKey), bad)
+	}
+	return malformed
+}
+
+// setArg stores a single key/value argument under the logger's groups
+func (l *logger) setArg(record *Record, key string, value interface{}) {
+	path := l.groupKey(key)
+
+	// Errors are expanded before structs so error structs keep their message
+	if err, ok := asError(value); ok {
+		l.setError(record, path, err)
+	} else if slogValue, ok := value.(slog.Value); ok {
+		record.Attributes.SetAttr(l.groups, slog.Attr{Key: key, Value: slogValue})
+	} else if shouldExpandStruct(value) {
+		record.Attributes.ExpandStruct(path, value)
+	} else {
+		// Use optimized SetFast directly for non-struct values
+		record.Attributes.SetFast(path, value)
 	}
 }
 
-// processArgsOptimized is an optimized version of processArgs
-func (l *logger) processArgsOptimized(record *Record, args ...interface{}) {
-	if len(args) == 0 {
-		return
+// setAttrArg stores a single Attr or slog.Attr argument under the logger's
+// groups and reports whether the argument was one
+func (l *logger) setAttrArg(record *Record, arg interface{}) bool {
+	switch attr := arg.(type) {
+	case Attr:
+		l.setAttr(record, attr)
+	case slog.Attr:
+		if err, ok := asError(attr.Value.Any()); ok {
+			l.setError(record, l.groupKey(attr.Key), err)
+		} else {
+			record.Attributes.SetAttr(l.groups, attr)
+		}
+	default:
+		return false
 	}
+	return true
+}
 
-	// Fast path for no groups (most common case)
-	if len(l.groups) == 0 {
-		for i := 0; i < len(args); i += 2 {
-			if i+1 >= len(args) {
-				break
-			}
-
-			key, ok := args[i].(string)
-			if !ok {
-				continue
-			}
-
-			value := args[i+1]
-			
-			// Check if value is a struct and should be expanded
-			if l.shouldExpandStruct(value) {
-				record.Attributes.ExpandStruct(key, value)
-			} else {
-				// Use optimized SetFast directly for non-struct values
-				record.Attributes.SetFast(key, value)
-			}
-		}
+// setAttr stores a typed attribute under the logger's groups
+func (l *logger) setAttr(record *Record, attr Attr) {
+	if err, ok := asError(attr.Value.Any()); ok {
+		l.setError(record, l.groupKey(attr.Key), err)
 		return
 	}
+	record.Attributes.SetValue(l.groupKey(attr.Key), attr.Value)
+}
 
-	// Slower path with groups - use pre-allocated paths where possible
-	for i := 0; i < len(args); i += 2 {
-		if i+1 >= len(args) {
-			break
-		}
-
-		key, ok := args[i].(string)
-		if !ok {
-			continue
-		}
-
-		value := args[i+1]
-
-		// Build path with groups
-		keyPath := make([]string, len(l.groups)+1)
-		copy(keyPath, l.groups)
-		keyPath[len(l.groups)] = key
+// setError expands err under key, adding the call stack when enabled for the record's level
+func (l *logger) setError(record *Record, key string, err error) {
+	record.Attributes.SetError(key, err)
+	if l.errStack != nil && record.Level >= *l.errStack {
+		record.Attributes.SetByDotNotation(key+".stack", callerStack(l.skip))
+	}
+}
 
-		// Check if value is a struct and should be expanded
-		if l.shouldExpandStruct(value) {
-			pathStr := key
-			if len(l.groups) > 0 {
-				pathStr = fmt.Sprintf("%s.%s", strings.Join(l.groups, "."), key)
-			}
-			record.Attributes.ExpandStruct(pathStr, value)
-		} else {
-			record.Attributes.Set(keyPath, value)
-		}
+// groupKey prefixes key with the logger's groups in dot notation
+func (l *logger) groupKey(key string) string {
+	if len(l.groups) == 0 {
+		return key
 	}
+	return strings.Join(l.groups, ".") + "." + key
 }
 
-// shouldExpandStruct determines if a value should be expanded as a struct
-func (l *logger) shouldExpandStruct(value interface{}) bool {
-	if value == nil {
+// shouldExpandStruct determines if a value should be expanded as a struct;
+// LogValuers are left intact until they are resolved
+func shouldExpandStruct(value interface{}) bool {
+	if value == nil || isLogValuer(value) {
 		return false
 	}
 
@@ -235,15 +338,16 @@ func (l *logger) Error(msg string, args ...interface{}) {
 	l.Log(context.Background(), LevelError, msg, args...)
 }
 
-// Fatal logs a message at fatal level
+// Fatal logs a message at fatal level, runs exit hooks, flushes and closes
+// the handler's buffers, and exits the process
 func (l *logger) Fatal(msg string, args ...interface{}) {
 	l.Log(context.Background(), LevelFatal, msg, args...)
+	exit(l.Handler(), 1)
 }
 
-// Panic logs a message at panic level and panics
+// Panic logs a message at panic level and panics with a *PanicError
 func (l *logger) Panic(msg string, args ...interface{}) {
-	l.Log(context.Background(), LevelPanic, msg, args...)
-	panic(fmt.Sprintf(msg, args...))
+	panic(l.logPanic(context.Background(), l.handler, "", msg, args))
 }
 
 // Mark logs a message at mark level for logical separation
@@ -251,17 +355,62 @@ func (l *logger) Mark(msg string, args ...interface{}) {
 	l.Log(context.Background(), LevelMark, msg, args...)
 }
 
+// TraceContext logs a message at trace level with the given context
+func (l *logger) TraceContext(ctx context.Context, msg string, args ...interface{}) {
+	l.Log(ctx, LevelTrace, msg, args...)
+}
+
+// DebugContext logs a message at debug level with the given context
+func (l *logger) DebugContext(ctx context.Context, msg string, args ...interface{}) {
+	l.Log(ctx, LevelDebug, msg, args...)
+}
+
+// InfoContext logs a message at info level with the given context
+func (l *logger) InfoContext(ctx context.Context, msg string, args ...interface{}) {
+	l.Log(ctx, LevelInfo, msg, args...)
+}
+
+// WarnContext logs a message at warn level with the given context
+func (l *logger) WarnContext(ctx context.Context, msg string, args ...interface{}) {
+	l.Log(ctx, LevelWarn, msg, args...)
+}
+
+// ErrorContext logs a message at error level with the given context
+func (l *logger) ErrorContext(ctx context.Context, msg string, args ...interface{}) {
+	l.Log(ctx, LevelError, msg, args...)
+}
+
+// FatalContext logs a message at fatal level with the given context and exits the process
+func (l *logger) FatalContext(ctx context.Context, msg string, args ...interface{}) {
+	l.Log(ctx, LevelFatal, msg, args...)
+	exit(l.Handler(), 1)
+}
+
+// PanicContext logs a message at panic level with the given context and panics with a *PanicError
+func (l *logger) PanicContext(ctx context.Context, msg string, args ...interface{}) {
+	panic(l.logPanic(ctx, l.handler, "", msg, args))
+}
+
+// MarkContext logs a message at mark level with the given context
+func (l *logger) MarkContext(ctx context.Context, msg string, args ...interface{}) {
+	l.Log(ctx, LevelMark, msg, args...)
+}
+
 // WithNested returns a logger with nested attributes
 func (l *logger) WithNested(keyPath []string, value interface{}) Logger {
 	newLogger := l.clone()
-	newLogger.attrs.Set(keyPath, value)
+	if !newLogger.attrs.setSlogValue(strings.Join(keyPath, "."), value) {
+		newLogger.attrs.Set(keyPath, value)
+	}
 	return newLogger
 }
 
 // WithDot returns a logger with dot notation attributes
 func (l *logger) WithDot(dotPath string, value interface{}) Logger {
 	newLogger := l.clone()
-	newLogger.attrs.SetByDotNotation(dotPath, value)
+	if !newLogger.attrs.setSlogValue(dotPath, value) {
+		newLogger.attrs.SetByDotNotation(dotPath, value)
+	}
 	return newLogger
 }
 
@@ -272,6 +421,28 @@ func (l *logger) WithGroup(name string) Logger {
 	return newLogger
 }
 
+// Named returns a logger whose name is the current name extended with name,
+// joined by a dot, e.g. Named("billing").Named("invoices") is "billing.invoices"
+func (l *logger) Named(name string) Logger {
+	newLogger := l.clone()
+	switch {
+	case name == "":
+	case newLogger.name == "":
+		newLogger.name = name
+	default:
+		newLogger.name += "." + name
+	}
+	return newLogger
+}
+
+// WithLevelRegistry returns a logger that takes its level from registry based
+// on the logger name, bypassing the handler's level when a rule matches
+func (l *logger) WithLevelRegistry(registry *LevelRegistry) Logger {
+	newLogger := l.clone()
+	newLogger.levels = registry
+	return newLogger
+}
+
 // WithCallback returns a logger with a callback
 func (l *logger) WithCallback(fn CallbackFunc) Logger {
 	newLogger := l.clone()
@@ -281,6 +452,48 @@ func (l *logger) WithCallback(fn CallbackFunc) Logger {
 	return newLogger
 }
 
+// WithErrorHandler returns a logger that reports handler errors to fn
+func (l *logger) WithErrorHandler(fn ErrorHandler) Logger {
+	newLogger := l.clone()
+	newLogger.onError = fn
+	return newLogger
+}
+
+// WithCallerSkip returns a logger that reports the source skip frames
+// further up the stack, for wrappers that log on behalf of their callers.
+// Skips add up across calls. Frames inside sawmill and functions marked with
+// MarkHelper are always skipped.
+func (l *logger) WithCallerSkip(skip int) Logger {
+	newLogger := l.clone()
+	newLogger.skip += skip
+	return newLogger
+}
+
+// WithStrictArgs returns a logger that reports malformed key/value
+// arguments to its error handler. The record is still written with the
+// malformed arguments under !BADKEY.
+func (l *logger) WithStrictArgs(enabled bool) Logger {
+	newLogger := l.clone()
+	newLogger.strict = enabled
+	return newLogger
+}
+
+// WithErrorStack returns a logger that records the call stack under
+// <key>.stack for error attributes of records at or above level
+func (l *logger) WithErrorStack(level Level) Logger {
+	newLogger := l.clone()
+	newLogger.errStack = &level
+	return newLogger
+}
+
+// WithHandler returns a logger that keeps the attributes, groups, name and
+// callbacks but writes to handler, e.g. a request-scoped wrapper of Handler()
+func (l *logger) WithHandler(handler Handler) Logger {
+	newLogger := l.clone()
+	newLogger.handler = handler
+	return newLogger
+}
+
 // SetHandler sets the handler for the logger
 func (l *logger) SetHandler(handler Handler) {
 	l.mu.Lock()
@@ -310,6 +523,14 @@ func (l *logger) clone() *logger {
 		attrs:     l.attrs.Clone(),
 		groups:    newGroups,
 		callbacks: newCallbacks,
+		onError:   l.onError,
+		name:      l.name,
+		levels:    l.levels,
+		errStack:  l.errStack,
+		skip:      l.skip,
+		strict:    l.strict,
+		timer:     l.timer,
+		section:   l.section,
 	}
 }
 
@@ -318,12 +539,12 @@ func (l *logger) As(formatter Formatter) AsLogger {
 	return &asLogger{
 		logger:    l,
 		formatter: formatter,
-		outputID:  l.generateOutputID(),
+		outputID:  generateOutputID(),
 	}
 }
 
 // generateOutputID creates a unique identifier for correlating multiline outputs
-func (l *logger) generateOutputID() string {
+func generateOutputID() string {
 	bytes := make([]byte, 4) // 8 character hex string
 	rand.Read(bytes)
 	return hex.EncodeToString(bytes)
@@ -338,44 +559,27 @@ type asLogger struct {
 
 // Log logs a message using the temporary formatter
 func (al *asLogger) Log(ctx context.Context, level Level, msg string, args ...interface{}) {
-	if !al.logger.handler.Enabled(ctx, level) {
+	if !al.logger.enabled(ctx, level) {
 		return
 	}
 
-	record := NewRecordFromPool(level, msg)
-	record.Context = ctx
-	record.OutputID = al.outputID
-
 	// Only capture frame if the handler/formatter might need it
+	var pc uintptr
 	if al.logger.needsSourceCapture() {
-		var pcs [1]uintptr
-		runtime.Callers(3, pcs[:])
-		record.PC = pcs[0]
+		pc = callerPC(al.logger.skip)
 	}
 
-	record.Attributes.Merge(al.logger.attrs)
-	al.logger.processArgsOptimized(record, args...)
-
-	al.logger.mu.RLock()
-	for _, callback := range al.logger.callbacks {
-		record = callback(record)
-	}
-	al.logger.mu.RUnlock()
+	record := al.logger.newRecord(ctx, level, msg, pc, args)
+	record.OutputID = al.outputID
+	al.logger.dispatch(ctx, al.handler(), record)
+}
 
-	// Create a temporary handler with our custom formatter
-	tempHandler := &temporaryHandler{
+// handler creates a temporary handler with the custom formatter
+func (al *asLogger) handler() Handler {
+	return &temporaryHandler{
 		originalHandler: al.logger.handler,
 		formatter:       al.formatter,
 	}
-
-	err := tempHandler.Handle(ctx, record)
-
-	// Return record to pool after use
-	ReturnRecordToPool(record)
-
-	if err != nil {
-		// Log error handling could be added here if needed
-	}
 }
 
 // Trace logs a message at trace level using the temporary formatter
@@ -403,15 +607,15 @@ func (al *asLogger) Error(msg string, args ...interface{}) {
 	al.Log(context.Background(), LevelError, msg, args...)
 }
 
-// Fatal logs a message at fatal level using the temporary formatter
+// Fatal logs a message at fatal level using the temporary formatter and exits the process
 func (al *asLogger) Fatal(msg string, args ...interface{}) {
 	al.Log(context.Background(), LevelFatal, msg, args...)
+	exit(al.logger.Handler(), 1)
 }
 
-// Panic logs a message at panic level using the temporary formatter and panics
+// Panic logs a message at panic level using the temporary formatter and panics with a *PanicError
 func (al *asLogger) Panic(msg string, args ...interface{}) {
-	al.Log(context.Background(), LevelPanic, msg, args...)
-	panic(fmt.Sprintf(msg, args...))
+	panic(al.logger.logPanic(context.Background(), al.handler(), al.outputID, msg, args))
 }
 
 // Mark logs a message at mark level using the temporary formatter
@@ -419,19 +623,63 @@ func (al *asLogger) Mark(msg string, args ...interface{}) {
 	al.Log(context.Background(), LevelMark, msg, args...)
 }
 
+// TraceContext logs a message at trace level with the given context using the temporary formatter
+func (al *asLogger) TraceContext(ctx context.Context, msg string, args ...interface{}) {
+	al.Log(ctx, LevelTrace, msg, args...)
+}
+
+// DebugContext logs a message at debug level with the given context using the temporary formatter
+func (al *asLogger) DebugContext(ctx context.Context, msg string, args ...interface{}) {
+	al.Log(ctx, LevelDebug, msg, args...)
+}
+
+// InfoContext logs a message at info level with the given context using the temporary formatter
+func (al *asLogger) InfoContext(ctx context.Context, msg string, args ...interface{}) {
+	al.Log(ctx, LevelInfo, msg, args...)
+}
+
+// WarnContext logs a message at warn level with the given context using the temporary formatter
+func (al *asLogger) WarnContext(ctx context.Context, msg string, args ...interface{}) {
+	al.Log(ctx, LevelWarn, msg, args...)
+}
+
+// ErrorContext logs a message at error level with the given context using the temporary formatter
+func (al *asLogger) ErrorContext(ctx context.Context, msg string, args ...interface{}) {
+	al.Log(ctx, LevelError, msg, args...)
+}
+
+// FatalContext logs a message at fatal level with the given context using the temporary formatter and exits the process
+func (al *asLogger) FatalContext(ctx context.Context, msg string, args ...interface{}) {
+	al.Log(ctx, LevelFatal, msg, args...)
+	exit(al.logger.Handler(), 1)
+}
+
+// PanicContext logs a message at panic level with the given context using the temporary formatter and panics with a *PanicError
+func (al *asLogger) PanicContext(ctx context.Context, msg string, args ...interface{}) {
+	panic(al.logger.logPanic(ctx, al.handler(), al.outputID, msg, args))
+}
+
+// MarkContext logs a message at mark level with the given context using the temporary formatter
+func (al *asLogger) MarkContext(ctx context.Context, msg string, args ...interface{}) {
+	al.Log(ctx, LevelMark, msg, args...)
+}
+
 // slogCompatibility provides slog compatibility
 func (l *logger) WithAttrs(attrs []slog.Attr) Logger {
 	newLogger := l.clone()
 	for _, attr := range attrs {
-		keyPath := make([]string, len(l.groups))
-		copy(keyPath, l.groups)
-		keyPath = append(keyPath, attr.Key)
-		newLogger.attrs.Set(keyPath, attr.Value.Any())
+		if err, ok := asError(attr.Value.Any()); ok {
+			newLogger.attrs.SetError(l.groupKey(attr.Key), err)
+		} else {
+			newLogger.attrs.SetAttr(l.groups, attr)
+		}
 	}
 	return newLogger
 }
 
-// HTTPErrorLog returns a *log.Logger compatible with http.Server.ErrorLog
+// HTTPErrorLog returns a *log.Logger compatible with http.Server.ErrorLog.
+// Lines are logged at Error, except TLS handshake errors, which are logged
+// at Debug.
 //
 // Example usage:
 //   logger := sawmill.Default()
@@ -441,21 +689,5 @@ func (l *logger) WithAttrs(attrs []slog.Attr) Logger {
 //       ErrorLog: logger.HTTPErrorLog(),
 //   }
 func (l *logger) HTTPErrorLog() *log.Logger {
-	return log.New(&httpErrorLogWriter{logger: l}, "", 0)
-}
-
-// httpErrorLogWriter adapts sawmill logger for use with standard log.Logger
-type httpErrorLogWriter struct {
-	logger *logger
-}
-
-// Write implements io.Writer interface for HTTP error logging
-func (w *httpErrorLogWriter) Write(p []byte) (n int, err error) {
-	msg := string(p)
-	// Remove trailing newline if present
-	if len(msg) > 0 && msg[len(msg)-1] == '\n' {
-		msg = msg[:len(msg)-1]
-	}
-	w.logger.Error(msg)
-	return len(p), nil
+	return NewStdLogger(l, LevelError)
 }
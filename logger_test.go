@@ -32,6 +32,8 @@ func TestDefaultLogger(t *testing.T) {
 }
 
 func TestLoggerLevels(t *testing.T) {
+	defer SetExitFunc(SetExitFunc(func(code int) {}))
+
 	buf := &bytes.Buffer{}
 	logger := New(NewTextHandler(WithDestination(NewWriterDestination(buf)), WithLevel(LevelTrace)))
 
@@ -35,6 +35,8 @@ type SawmillOptions struct {
 	EnableTrace bool `json:"enable_trace,omitempty"`
 	// EnableMetrics indicates whether to enable metrics logging.
 	EnableMetrics bool `json:"enable_metrics,omitempty"`
+	// Filter is a filter expression that records must match to be written, e.g. `level >= warn && user.tier == "gold"`.
+	Filter string `json:"filter,omitempty"`
 }
 
 // SawmillOption is a function that configures SawmillOptions
@@ -179,6 +181,13 @@ func WithEnableMetrics(enable bool) SawmillOption {
 	}
 }
 
+// WithLogFilter sets the filter expression
+func WithLogFilter(filter string) SawmillOption {
+	return func(opts *SawmillOptions) {
+		opts.Filter = filter
+	}
+}
+
 // Validate checks the SawmillOptions for any invalid configurations.
 func (opts *SawmillOptions) Validate() error {
 	if opts.MaxSize <= 0 {
@@ -193,5 +202,10 @@ func (opts *SawmillOptions) Validate() error {
 	if opts.LogLevel != "debug" && opts.LogLevel != "info" && opts.LogLevel != "warn" && opts.LogLevel != "error" && opts.LogLevel != "fatal" && opts.LogLevel != "panic" && opts.LogLevel != "trace" {
 		return fmt.Errorf("invalid log_level: %s", opts.LogLevel)
 	}
+	if opts.Filter != "" {
+		if _, err := CompileFilter(opts.Filter); err != nil {
+			return fmt.Errorf("invalid filter: %w", err)
+		}
+	}
 	return nil
 }
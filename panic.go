@@ -0,0 +1,161 @@
+package sawmill
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// PanicError is the value passed to panic by Logger.Panic and Logger.PanicContext
+type PanicError struct {
+	Message    string                 // Log message
+	Attributes map[string]interface{} // Record attributes keyed by dot path
+	Function   string                 // Function that called Panic
+	File       string                 // Source file of the call
+	Line       int                    // Source line of the call
+}
+
+// Error formats the message followed by the attributes in key order
+func (e *PanicError) Error() string {
+	if len(e.Attributes) == 0 {
+		return e.Message
+	}
+
+	keys := make([]string, 0, len(e.Attributes))
+	for key := range e.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(e.Message)
+	for _, key := range keys {
+		fmt.Fprintf(&b, " %s=%v", key, e.Attributes[key])
+	}
+	return b.String()
+}
+
+// RecoverOptions configures Logger.Recover
+type RecoverOptions struct {
+	Message string                  // Message of the logged record
+	Repanic bool                    // Re-panic with the original value after logging
+	OnPanic func(value interface{}) // Called with the recovered value after logging
+}
+
+// DefaultRecoverOptions returns options that log the panic and swallow it
+func DefaultRecoverOptions() *RecoverOptions {
+	return &RecoverOptions{
+		Message: "Recovered from panic",
+	}
+}
+
+// logPanic logs a panic-level record and returns the matching PanicError.
+// The record is built even when the level is disabled so the error carries it.
+func (l *logger) logPanic(ctx context.Context, handler Handler, outputID string, msg string, args []interface{}) *PanicError {
+	pcs := []uintptr{callerPC(l.skip)}
+
+	record := l.newRecord(ctx, LevelPanic, msg, pcs[0], args)
+	record.OutputID = outputID
+
+	panicErr := &PanicError{
+		Message:    record.Message,
+		Attributes: record.Attributes.ToMap(),
+	}
+	if frame, _ := runtime.CallersFrames(pcs).Next(); frame.PC != 0 {
+		panicErr.Function = frame.Function
+		panicErr.File = frame.File
+		panicErr.Line = frame.Line
+	}
+
+	if !l.enabled(ctx, LevelPanic) {
+		ReturnRecordToPool(record)
+		return panicErr
+	}
+
+	l.dispatch(ctx, handler, record)
+	return panicErr
+}
+
+// Recover recovers from a panic in the calling goroutine and logs it at panic
+// level with the goroutine stack trace. It must be called directly by defer.
+//
+// Example usage:
+//
+//	defer logger.Recover(nil)
+func (l *logger) Recover(opts *RecoverOptions) {
+	value := recover()
+	if value == nil {
+		return
+	}
+	l.logRecovered(value, opts)
+}
+
+// logRecovered logs a recovered panic value and applies the recover options
+func (l *logger) logRecovered(value interface{}, opts *RecoverOptions) {
+	if opts == nil {
+		opts = DefaultRecoverOptions()
+	}
+
+	ctx := context.Background()
+	if l.enabled(ctx, LevelPanic) {
+		msg := opts.Message
+		if msg == "" {
+			msg = DefaultRecoverOptions().Message
+		}
+
+		record := l.newRecord(ctx, LevelPanic, msg, panicSitePC(), nil)
+		setPanicAttributes(record.Attributes, value)
+		record.Attributes.SetByDotNotation("panic.stack", string(debug.Stack()))
+		l.dispatch(ctx, l.handler, record)
+	}
+
+	if opts.OnPanic != nil {
+		opts.OnPanic(value)
+	}
+	if opts.Repanic {
+		panic(value)
+	}
+}
+
+// panicSitePC returns the program counter of the first non-runtime frame
+// below Recover, which is the panicking function
+func panicSitePC() uintptr {
+	var pcs [32]uintptr
+	n := runtime.Callers(4, pcs[:])
+
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			return frame.PC
+		}
+		if !more {
+			return 0
+		}
+	}
+}
+
+// setPanicAttributes expands a recovered value under the panic key
+func setPanicAttributes(attrs *FlatAttributes, value interface{}) {
+	attrs.SetByDotNotation("panic.type", fmt.Sprintf("%T", value))
+
+	switch v := value.(type) {
+	case *PanicError:
+		attrs.SetByDotNotation("panic.message", v.Message)
+		for key, attr := range v.Attributes {
+			attrs.SetByDotNotation("panic.attributes."+key, attr)
+		}
+		if v.File != "" {
+			attrs.SetByDotNotation("panic.source.function", v.Function)
+			attrs.SetByDotNotation("panic.source.file", v.File)
+			attrs.SetByDotNotation("panic.source.line", v.Line)
+		}
+	case error:
+		attrs.SetByDotNotation("panic.error", v.Error())
+	default:
+		attrs.ExpandStruct("panic.value", value)
+	}
+}
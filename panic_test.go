@@ -0,0 +1,128 @@
+package sawmill
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestPanicValue(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewTextHandler(WithWriter(buf))).WithDot("service", "billing")
+
+	var panicErr *PanicError
+	var line int
+	func() {
+		defer func() {
+			panicErr, _ = recover().(*PanicError)
+		}()
+		_, _, line, _ = runtime.Caller(0)
+		logger.Panic("Invariant violated", "user.id", 42)
+	}()
+
+	if panicErr == nil {
+		t.Fatal("Expected Panic to panic with a *PanicError")
+	}
+	if panicErr.Message != "Invariant violated" {
+		t.Errorf("Unexpected message: %q", panicErr.Message)
+	}
+	if panicErr.Attributes["user.id"] != 42 || panicErr.Attributes["service"] != "billing" {
+		t.Errorf("Expected record attributes in panic value: %v", panicErr.Attributes)
+	}
+	if !strings.HasSuffix(panicErr.File, "panic_test.go") || panicErr.Line != line+1 {
+		t.Errorf("Expected source of the Panic call, got %s:%d", panicErr.File, panicErr.Line)
+	}
+	if got := panicErr.Error(); got != "Invariant violated service=billing user.id=42" {
+		t.Errorf("Unexpected Error(): %q", got)
+	}
+	if strings.Contains(buf.String(), "%!") || !strings.Contains(buf.String(), "Invariant violated") {
+		t.Errorf("Unexpected output: %s", buf.String())
+	}
+}
+
+func TestPanicWhenLevelDisabled(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewTextHandler(WithWriter(buf), WithLevel(LevelMark)))
+
+	defer func() {
+		panicErr, ok := recover().(*PanicError)
+		if !ok || panicErr.Message != "Filtered" {
+			t.Errorf("Expected *PanicError even when level is disabled, got %v", panicErr)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("Expected no output for disabled level: %s", buf.String())
+		}
+	}()
+
+	logger.As(NewJSONFormatter()).Panic("Filtered")
+}
+
+func TestRecoverSwallowsPanic(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf)))
+
+	var recovered interface{}
+	func() {
+		defer logger.Recover(&RecoverOptions{
+			Message: "Worker crashed",
+			OnPanic: func(value interface{}) { recovered = value },
+		})
+		panic(errors.New("boom"))
+	}()
+
+	output := buf.String()
+	if !strings.Contains(output, "Worker crashed") || !strings.Contains(output, `"level":"PANIC"`) {
+		t.Errorf("Expected panic-level record: %s", output)
+	}
+	if !strings.Contains(output, `"panic.error":"boom"`) || !strings.Contains(output, `"panic.type":"*errors.errorString"`) {
+		t.Errorf("Expected panic value attributes: %s", output)
+	}
+	if !strings.Contains(output, "goroutine") {
+		t.Errorf("Expected stack trace in output: %s", output)
+	}
+	if err, ok := recovered.(error); !ok || err.Error() != "boom" {
+		t.Errorf("Expected OnPanic to receive the panic value, got %v", recovered)
+	}
+}
+
+func TestRecoverRepanics(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf)))
+
+	type failure struct {
+		Code   int
+		Reason string
+	}
+
+	defer func() {
+		if _, ok := recover().(failure); !ok {
+			t.Error("Expected the original panic value to be re-panicked")
+		}
+		if !strings.Contains(buf.String(), `"panic.value.code":7`) || !strings.Contains(buf.String(), `"panic.value.reason":"broken"`) {
+			t.Errorf("Expected struct panic value to be expanded: %s", buf.String())
+		}
+	}()
+
+	defer logger.Recover(&RecoverOptions{Repanic: true})
+	panic(failure{Code: 7, Reason: "broken"})
+}
+
+func TestRecoverPanicError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf)))
+
+	func() {
+		defer logger.Recover(nil)
+		logger.Panic("Order rejected", "order.id", "o-1")
+	}()
+
+	output := buf.String()
+	if !strings.Contains(output, "Recovered from panic") {
+		t.Errorf("Expected default recover message: %s", output)
+	}
+	if !strings.Contains(output, `"panic.message":"Order rejected"`) || !strings.Contains(output, `"panic.attributes.order.id":"o-1"`) {
+		t.Errorf("Expected PanicError to be expanded: %s", output)
+	}
+}
@@ -0,0 +1,145 @@
+package plugins
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/bresrch/sawmill"
+)
+
+// DeferredRequestOptions configures DeferredRequestLogging
+type DeferredRequestOptions struct {
+	Deferred       *sawmill.DeferredOptions // Buffer configuration; nil uses sawmill.DefaultDeferredOptions
+	Request        *HTTPRequestOptions      // Request fields added to every record; nil adds none
+	Response       *HTTPResponseOptions     // Response fields added to the summary line; nil adds none
+	FailureStatus  int                      // Responses with this status or above release the buffer
+	SummaryMessage string                   // Message of the access summary line
+}
+
+// DefaultDeferredRequestOptions returns sensible defaults for deferred request logging
+func DefaultDeferredRequestOptions() *DeferredRequestOptions {
+	return &DeferredRequestOptions{
+		Deferred:       sawmill.DefaultDeferredOptions(),
+		Request:        DefaultHTTPRequestOptions(),
+		Response:       DefaultHTTPResponseOptions(),
+		FailureStatus:  http.StatusInternalServerError,
+		SummaryMessage: "Request completed",
+	}
+}
+
+// DeferredRequestLogging returns middleware that collects the logs of each
+// request in a request-scoped buffer. The buffer is written only if the
+// request ends with a status at or above FailureStatus, panics, or logs at
+// the trigger level; otherwise only the access summary line is written.
+// Records written together share an OutputID. Handlers get the request
+// logger with sawmill.FromContext(r.Context()).
+func DeferredRequestLogging(logger sawmill.Logger, opts *DeferredRequestOptions) func(http.Handler) http.Handler {
+	if opts == nil {
+		opts = DefaultDeferredRequestOptions()
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			deferred := sawmill.NewDeferredHandler(logger.Handler(), opts.Deferred)
+
+			requestLogger := logger.WithHandler(deferred)
+			if opts.Request != nil {
+				requestLogger = WithHTTPRequestOptions(requestLogger, r, opts.Request)
+			}
+
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			defer func() {
+				recovered := recover()
+				if recovered != nil && !recorder.wroteHeader {
+					recorder.status = http.StatusInternalServerError
+				}
+
+				failed := recovered != nil || recorder.status >= opts.FailureStatus
+				if failed {
+					deferred.Release()
+				} else {
+					deferred.Discard()
+				}
+
+				summary := requestLogger.WithDot("http.response.duration_ms", time.Since(start).Milliseconds())
+				if opts.Response != nil {
+					summary = WithHTTPResponseWriterOptions(summary, recorder, opts.Response)
+				}
+				switch {
+				case recovered != nil:
+					summary.Error(opts.SummaryMessage, "panic", fmt.Sprint(recovered))
+				case recorder.status >= opts.FailureStatus:
+					summary.Error(opts.SummaryMessage)
+				default:
+					summary.Info(opts.SummaryMessage)
+				}
+
+				if recovered != nil {
+					panic(recovered)
+				}
+			}()
+
+			next.ServeHTTP(recorder, r.WithContext(sawmill.IntoContext(r.Context(), requestLogger)))
+		})
+	}
+}
+
+// statusRecorder captures the status code and size of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	size        int64
+	wroteHeader bool
+}
+
+func (w *statusRecorder) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+// Status returns the response status code
+func (w *statusRecorder) Status() int {
+	return w.status
+}
+
+// Size returns the number of body bytes written
+func (w *statusRecorder) Size() int64 {
+	return w.size
+}
+
+// Flush implements http.Flusher for streaming handlers when the underlying writer supports it
+func (w *statusRecorder) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		flusher.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker when the underlying writer supports it
+func (w *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	w.wroteHeader = true
+	return hijacker.Hijack()
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
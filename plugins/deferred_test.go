@@ -0,0 +1,148 @@
+package plugins
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bresrch/sawmill"
+)
+
+// serveDeferred runs handler behind DeferredRequestLogging and returns the decoded log lines
+func serveDeferred(t *testing.T, handler http.HandlerFunc) []map[string]interface{} {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	logger := sawmill.New(sawmill.NewJSONHandler(sawmill.WithWriter(buf), sawmill.WithSourceInfo(false)))
+	middleware := DeferredRequestLogging(logger, nil)(handler)
+
+	func() {
+		defer func() { recover() }()
+		middleware.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+	}()
+
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid JSON %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// messages returns the message of every entry
+func messages(entries []map[string]interface{}) []string {
+	result := make([]string, len(entries))
+	for i, entry := range entries {
+		result[i], _ = entry["message"].(string)
+	}
+	return result
+}
+
+func TestDeferredRequestLoggingSuccess(t *testing.T) {
+	entries := serveDeferred(t, func(w http.ResponseWriter, r *http.Request) {
+		sawmill.FromContext(r.Context()).Debug("Loading order")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	if len(entries) != 1 || entries[0]["message"] != "Request completed" || entries[0]["level"] != "INFO" {
+		t.Fatalf("Expected only the summary line, got %v", messages(entries))
+	}
+	if entries[0]["output_id"] != nil {
+		t.Errorf("Expected no output ID without a release: %v", entries[0])
+	}
+}
+
+func TestDeferredRequestLoggingFailureStatus(t *testing.T) {
+	entries := serveDeferred(t, func(w http.ResponseWriter, r *http.Request) {
+		sawmill.FromContext(r.Context()).Debug("Loading order")
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	if got := messages(entries); len(got) != 2 || got[0] != "Loading order" || got[1] != "Request completed" {
+		t.Fatalf("Expected the buffered line and the summary, got %v", got)
+	}
+	if entries[1]["level"] != "ERROR" {
+		t.Errorf("Expected the summary at Error: %v", entries[1])
+	}
+	attrs := entries[1]["attributes"].(map[string]interface{})
+	if attrs["http.response.status_code"] != float64(http.StatusBadGateway) {
+		t.Errorf("Expected the response status on the summary: %v", attrs)
+	}
+}
+
+func TestDeferredRequestLoggingPanic(t *testing.T) {
+	entries := serveDeferred(t, func(w http.ResponseWriter, r *http.Request) {
+		sawmill.FromContext(r.Context()).Debug("Loading order")
+		panic("nil order")
+	})
+
+	if got := messages(entries); len(got) != 2 || got[0] != "Loading order" {
+		t.Fatalf("Expected the buffered line and the summary, got %v", got)
+	}
+	if attrs := entries[1]["attributes"].(map[string]interface{}); attrs["panic"] != "nil order" {
+		t.Errorf("Expected the panic value on the summary: %v", attrs)
+	}
+}
+
+func TestDeferredRequestLoggingErrorRecord(t *testing.T) {
+	entries := serveDeferred(t, func(w http.ResponseWriter, r *http.Request) {
+		logger := sawmill.FromContext(r.Context())
+		logger.Debug("Loading order")
+		logger.Error("Order not found")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	if got := messages(entries); len(got) != 3 || got[0] != "Loading order" || got[1] != "Order not found" || got[2] != "Request completed" {
+		t.Fatalf("Expected the buffered lines and the summary, got %v", got)
+	}
+}
+
+func TestDeferredRequestLoggingSharedOutputID(t *testing.T) {
+	entries := serveDeferred(t, func(w http.ResponseWriter, r *http.Request) {
+		logger := sawmill.FromContext(r.Context())
+		logger.Debug("Loading order")
+		logger.Info("Calling payment service")
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 records, got %v", messages(entries))
+	}
+	outputID := entries[0]["output_id"]
+	if outputID == nil {
+		t.Fatalf("Expected flushed lines to carry an output ID: %v", entries[0])
+	}
+	for _, entry := range entries {
+		if entry["output_id"] != outputID {
+			t.Errorf("Expected every flushed line to share output ID %v: %v", outputID, entry)
+		}
+	}
+}
+
+func TestDeferredRequestLoggingStreaming(t *testing.T) {
+	logger := sawmill.New(sawmill.NewJSONHandler(sawmill.WithWriter(&bytes.Buffer{})))
+	middleware := DeferredRequestLogging(logger, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("Expected the wrapped writer to implement http.Flusher")
+		}
+		w.Write([]byte("data: ready\n\n"))
+		flusher.Flush()
+
+		if _, _, err := w.(http.Hijacker).Hijack(); err != http.ErrNotSupported {
+			t.Errorf("Expected Hijack to report an unsupported writer, got %v", err)
+		}
+	}))
+
+	recorder := httptest.NewRecorder()
+	middleware.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	if !recorder.Flushed || recorder.Body.String() != "data: ready\n\n" {
+		t.Errorf("Expected Flush to reach the underlying writer: flushed %v, body %q", recorder.Flushed, recorder.Body.String())
+	}
+}
@@ -76,6 +76,11 @@ func NewRecordFromPool(level Level, msg string) *Record {
 	record.Time = time.Now()
 	record.Context = nil
 	record.PC = 0
+	record.OutputID = ""
+	record.LoggerName = ""
+	record.levelOverride = false
+	record.sectionDepth = 0
+	record.correlated = false
 	record.Attributes.reset() // Ensure clean attributes
 	return record
 }
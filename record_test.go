@@ -0,0 +1,102 @@
+package sawmill
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestRecordClone(t *testing.T) {
+	record := NewRecordFromPool(LevelWarn, "Original")
+	record.OutputID = "abc123"
+	record.LoggerName = "billing"
+	record.Attributes.SetByDotNotation("user.id", 42)
+
+	clone := record.Clone()
+	record.Attributes.SetByDotNotation("user.id", 7)
+	ReturnRecordToPool(record)
+	NewRecordFromPool(LevelInfo, "Reused").Attributes.SetByDotNotation("other", true)
+
+	if clone.Message != "Original" || clone.Level != LevelWarn || clone.OutputID != "abc123" || clone.LoggerName != "billing" {
+		t.Errorf("Expected record fields to be copied: %+v", clone)
+	}
+	if value, _ := clone.Attributes.GetByDotNotation("user.id"); value != 42 {
+		t.Errorf("Expected clone attributes to be independent, got %v", value)
+	}
+	if clone.Attributes.Has([]string{"other"}) {
+		t.Error("Expected clone to be unaffected by pool reuse")
+	}
+}
+
+func TestFlatAttributesCloneSmallData(t *testing.T) {
+	attrs := &FlatAttributes{}
+	attrs.SetFast("a", 1)
+	attrs.SetFast("b", 2)
+
+	clone := attrs.Clone()
+	attrs.SetFast("a", 100)
+
+	if clone.Size() != 2 {
+		t.Errorf("Expected small-data entries to be cloned, got size %d", clone.Size())
+	}
+	if value, _ := clone.Get([]string{"a"}); value != 1 {
+		t.Errorf("Expected cloned small-data value 1, got %v", value)
+	}
+
+	merged := NewFlatAttributes()
+	merged.Merge(attrs)
+	if value, _ := merged.Get([]string{"b"}); value != 2 {
+		t.Errorf("Expected Merge to include small-data entries, got %v", value)
+	}
+}
+
+// capturingHandler keeps every record it handles
+type capturingHandler struct {
+	records []*Record
+}
+
+func (h *capturingHandler) Handle(ctx context.Context, record *Record) error {
+	h.records = append(h.records, record)
+	return nil
+}
+
+func (h *capturingHandler) WithAttrs(attrs []slog.Attr) Handler           { return h }
+func (h *capturingHandler) WithGroup(name string) Handler                 { return h }
+func (h *capturingHandler) Enabled(ctx context.Context, level Level) bool { return true }
+func (h *capturingHandler) RetainsRecords() bool                          { return true }
+
+func TestRecordRetainerReceivesDetachedRecords(t *testing.T) {
+	capture := &capturingHandler{}
+	logger := New(NewMultiHandler(NewTextHandler(WithWriter(&syncBuffer{})), capture))
+
+	logger.Info("First", "seq", 1)
+	logger.Info("Second", "seq", 2)
+	for i := 0; i < 10; i++ {
+		NewRecordFromPool(LevelDebug, "Churn").Attributes.SetByDotNotation("seq", -1)
+	}
+
+	if len(capture.records) != 2 {
+		t.Fatalf("Expected 2 captured records, got %d", len(capture.records))
+	}
+	for i, record := range capture.records {
+		if value, _ := record.Attributes.GetByDotNotation("seq"); value != i+1 {
+			t.Errorf("Expected retained record %d to keep its attributes, got %v", i, value)
+		}
+	}
+}
+
+func TestDispatchChecksTheHandlerItIsGiven(t *testing.T) {
+	capture := &capturingHandler{}
+	logger := New(NewTextHandler(WithWriter(&syncBuffer{}))).(*logger)
+
+	record := NewRecordFromPool(LevelInfo, "Dispatched")
+	record.Attributes.SetByDotNotation("seq", 1)
+	logger.dispatch(context.Background(), capture, record)
+	for i := 0; i < 10; i++ {
+		NewRecordFromPool(LevelDebug, "Churn").Attributes.SetByDotNotation("seq", -1)
+	}
+
+	if value, _ := capture.records[0].Attributes.GetByDotNotation("seq"); value != 1 {
+		t.Errorf("Expected the retained record to stay out of the pool, got %v", value)
+	}
+}
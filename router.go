@@ -0,0 +1,355 @@
+package sawmill
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+// RouteMatcher reports whether a record belongs to a route
+type RouteMatcher func(record *Record) bool
+
+// Route sends matching records to a handler
+type Route struct {
+	Name     string       // Name used by Explain and Stats
+	Match    RouteMatcher // Predicate over the record; nil matches every record
+	Handler  Handler      // Destination for matching records
+	Continue bool         // Keep evaluating later routes after this one matches
+}
+
+// RouteDecision describes how a route treated a record
+type RouteDecision struct {
+	Route   string // Route name, or "default" for the default route
+	Matched bool   // Whether the route's matcher accepted the record
+	Stopped bool   // Whether routing stopped after this route
+}
+
+// RouterHandler dispatches each record to the first matching route, in order.
+// A route with Continue set lets later routes see the record too. Records
+// that match no route go to the default handler, if any.
+type RouterHandler struct {
+	routes         []Route
+	defaultHandler Handler
+	counters       []*atomic.Uint64 // per route, with the default route last
+}
+
+// NewRouterHandler creates a router with ordered routes and an optional default handler
+func NewRouterHandler(defaultHandler Handler, routes ...Route) *RouterHandler {
+	counters := make([]*atomic.Uint64, len(routes)+1)
+	for i := range counters {
+		counters[i] = &atomic.Uint64{}
+	}
+
+	return &RouterHandler{
+		routes:         routes,
+		defaultHandler: defaultHandler,
+		counters:       counters,
+	}
+}
+
+func (h *RouterHandler) Handle(ctx context.Context, record *Record) error {
+	var errs []error
+	matched := false
+
+	for i, route := range h.routes {
+		if route.Match != nil && !route.Match(record) {
+			continue
+		}
+
+		matched = true
+		h.counters[i].Add(1)
+		if route.Handler != nil {
+			if err := route.Handler.Handle(ctx, record); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if !route.Continue {
+			break
+		}
+	}
+
+	if !matched && h.defaultHandler != nil {
+		h.counters[len(h.routes)].Add(1)
+		if err := h.defaultHandler.Handle(ctx, record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Explain returns the routing decision for a record without handling it
+func (h *RouterHandler) Explain(record *Record) []RouteDecision {
+	decisions := make([]RouteDecision, 0, len(h.routes)+1)
+	matched := false
+
+	for _, route := range h.routes {
+		decision := RouteDecision{Route: route.Name}
+		if route.Match == nil || route.Match(record) {
+			matched = true
+			decision.Matched = true
+			decision.Stopped = !route.Continue
+		}
+		decisions = append(decisions, decision)
+		if decision.Stopped {
+			return decisions
+		}
+	}
+
+	if h.defaultHandler != nil {
+		decisions = append(decisions, RouteDecision{Route: "default", Matched: !matched, Stopped: true})
+	}
+	return decisions
+}
+
+// Stats returns the number of records each route has received, keyed by route name.
+// Records sent to the default handler are counted under "default".
+func (h *RouterHandler) Stats() map[string]uint64 {
+	stats := make(map[string]uint64, len(h.routes)+1)
+	for i, route := range h.routes {
+		stats[route.Name] += h.counters[i].Load()
+	}
+	if h.defaultHandler != nil {
+		stats["default"] += h.counters[len(h.routes)].Load()
+	}
+	return stats
+}
+
+// handlers returns every route handler followed by the default handler
+func (h *RouterHandler) handlers() []Handler {
+	handlers := make([]Handler, 0, len(h.routes)+1)
+	for _, route := range h.routes {
+		if route.Handler != nil {
+			handlers = append(handlers, route.Handler)
+		}
+	}
+	if h.defaultHandler != nil {
+		handlers = append(handlers, h.defaultHandler)
+	}
+	return handlers
+}
+
+// withHandlers returns a router with the same routes and counters and transformed handlers
+func (h *RouterHandler) withHandlers(transform func(Handler) Handler) *RouterHandler {
+	routes := make([]Route, len(h.routes))
+	for i, route := range h.routes {
+		routes[i] = route
+		if route.Handler != nil {
+			routes[i].Handler = transform(route.Handler)
+		}
+	}
+
+	var defaultHandler Handler
+	if h.defaultHandler != nil {
+		defaultHandler = transform(h.defaultHandler)
+	}
+
+	return &RouterHandler{routes: routes, defaultHandler: defaultHandler, counters: h.counters}
+}
+
+func (h *RouterHandler) WithAttrs(attrs []slog.Attr) Handler {
+	return h.withHandlers(func(handler Handler) Handler { return handler.WithAttrs(attrs) })
+}
+
+func (h *RouterHandler) WithGroup(name string) Handler {
+	return h.withHandlers(func(handler Handler) Handler { return handler.WithGroup(name) })
+}
+
+func (h *RouterHandler) Enabled(ctx context.Context, level Level) bool {
+	for _, handler := range h.handlers() {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsSource reports whether any route handler needs source information
+func (h *RouterHandler) NeedsSource() bool {
+	for _, handler := range h.handlers() {
+		if handlerNeedsSource(handler) {
+			return true
+		}
+	}
+	return false
+}
+
+// RetainsRecords implements RecordRetainer by checking every route handler
+func (h *RouterHandler) RetainsRecords() bool {
+	for _, handler := range h.handlers() {
+		if handlerRetainsRecords(handler) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handlers returns the route handlers followed by the default handler
+func (h *RouterHandler) Handlers() []Handler {
+	return h.handlers()
+}
+
+// Flush implements Flusher by flushing every route handler
+func (h *RouterHandler) Flush() error {
+	var errs []error
+	for _, handler := range h.handlers() {
+		errs = append(errs, flushHandler(handler))
+	}
+	return errors.Join(errs...)
+}
+
+// Close implements Closer by closing every route handler
+func (h *RouterHandler) Close() error {
+	var errs []error
+	for _, handler := range h.handlers() {
+		errs = append(errs, closeHandler(handler))
+	}
+	return errors.Join(errs...)
+}
+
+// MatchLevel matches records at or above level
+func MatchLevel(level Level) RouteMatcher {
+	return func(record *Record) bool {
+		return record.Level >= level
+	}
+}
+
+// MatchLevelBelow matches records below level
+func MatchLevelBelow(level Level) RouteMatcher {
+	return func(record *Record) bool {
+		return record.Level < level
+	}
+}
+
+// MatchMessage matches records whose message contains substr
+func MatchMessage(substr string) RouteMatcher {
+	return func(record *Record) bool {
+		return strings.Contains(record.Message, substr)
+	}
+}
+
+// MatchAttr matches records that have an attribute at the dot path
+func MatchAttr(dotPath string) RouteMatcher {
+	return func(record *Record) bool {
+		return record.Attributes.HasByDotNotation(dotPath)
+	}
+}
+
+// MatchAttrValue matches records whose attribute at the dot path equals value.
+// Numbers are compared by value across integer, unsigned and float types;
+// slices, maps and other uncomparable values are compared with reflect.DeepEqual
+func MatchAttrValue(dotPath string, value interface{}) RouteMatcher {
+	return func(record *Record) bool {
+		actual, ok := record.Attributes.GetByDotNotation(dotPath)
+		return ok && attrValuesEqual(actual, value)
+	}
+}
+
+// attrValuesEqual compares attribute values without panicking on uncomparable types
+func attrValuesEqual(a, b interface{}) bool {
+	if equal, ok := numbersEqual(reflect.ValueOf(a), reflect.ValueOf(b)); ok {
+		return equal
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	if reflect.ValueOf(a).Comparable() && reflect.ValueOf(b).Comparable() {
+		return a == b
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// numbersEqual compares integer, unsigned and float values by value and
+// reports whether both were numbers
+func numbersEqual(a, b reflect.Value) (equal bool, ok bool) {
+	kindA, kindB := numberKind(a), numberKind(b)
+	if kindA == 0 || kindB == 0 {
+		return false, false
+	}
+
+	switch {
+	case kindA == 'i' && kindB == 'i':
+		return a.Int() == b.Int(), true
+	case kindA == 'u' && kindB == 'u':
+		return a.Uint() == b.Uint(), true
+	case kindA == 'i' && kindB == 'u':
+		return a.Int() >= 0 && uint64(a.Int()) == b.Uint(), true
+	case kindA == 'u' && kindB == 'i':
+		return b.Int() >= 0 && uint64(b.Int()) == a.Uint(), true
+	default:
+		return numberFloat(a, kindA) == numberFloat(b, kindB), true
+	}
+}
+
+// numberKind returns 'i', 'u' or 'f' for integer, unsigned and float values, or 0
+func numberKind(v reflect.Value) byte {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return 'i'
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return 'u'
+	case reflect.Float32, reflect.Float64:
+		return 'f'
+	default:
+		return 0
+	}
+}
+
+// numberFloat converts a number of the given kind to float64
+func numberFloat(v reflect.Value, kind byte) float64 {
+	switch kind {
+	case 'i':
+		return float64(v.Int())
+	case 'u':
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
+
+// MatchAttrPrefix matches records with any attribute under the dot path prefix,
+// e.g. "audit" matches "audit.user" and "audit.action.name"
+func MatchAttrPrefix(prefix string) RouteMatcher {
+	return func(record *Record) bool {
+		for _, key := range record.Attributes.Keys() {
+			if key == prefix || strings.HasPrefix(key, prefix+".") {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchAll matches records accepted by every matcher
+func MatchAll(matchers ...RouteMatcher) RouteMatcher {
+	return func(record *Record) bool {
+		for _, match := range matchers {
+			if !match(record) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MatchAny matches records accepted by at least one matcher
+func MatchAny(matchers ...RouteMatcher) RouteMatcher {
+	return func(record *Record) bool {
+		for _, match := range matchers {
+			if match(record) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchNot inverts a matcher
+func MatchNot(matcher RouteMatcher) RouteMatcher {
+	return func(record *Record) bool {
+		return !matcher(record)
+	}
+}
@@ -0,0 +1,129 @@
+package sawmill
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRouterHandler(t *testing.T) {
+	errBuf := &bytes.Buffer{}
+	auditBuf := &bytes.Buffer{}
+	defaultBuf := &bytes.Buffer{}
+
+	router := NewRouterHandler(NewTextHandler(WithWriter(defaultBuf)),
+		Route{Name: "errors", Match: MatchLevel(LevelError), Handler: NewJSONHandler(WithWriter(errBuf)), Continue: true},
+		Route{Name: "audit", Match: MatchAttrPrefix("audit"), Handler: NewJSONHandler(WithWriter(auditBuf))},
+	)
+	logger := New(router)
+
+	logger.Info("Request served", "path", "/health")
+	logger.Info("User updated", "audit.user", "alice", "audit.action", "update")
+	logger.Error("Audit write failed", "audit.user", "bob")
+	logger.Error("Database down")
+
+	if got := defaultBuf.String(); !strings.Contains(got, "Request served") || strings.Contains(got, "User updated") {
+		t.Errorf("Unexpected default output: %s", got)
+	}
+	if got := auditBuf.String(); !strings.Contains(got, "User updated") || !strings.Contains(got, "Audit write failed") {
+		t.Errorf("Expected audit records in audit output: %s", got)
+	}
+	if got := errBuf.String(); !strings.Contains(got, "Audit write failed") || !strings.Contains(got, "Database down") {
+		t.Errorf("Expected error records in error output: %s", got)
+	}
+
+	// Matched records skip the default route
+	if strings.Contains(defaultBuf.String(), "Database down") {
+		t.Error("Expected a matched record to skip the default route")
+	}
+
+	stats := router.Stats()
+	if stats["errors"] != 2 || stats["audit"] != 2 || stats["default"] != 1 {
+		t.Errorf("Unexpected stats: %v", stats)
+	}
+}
+
+func TestRouterHandlerExplain(t *testing.T) {
+	router := NewRouterHandler(NewTextHandler(WithWriter(&bytes.Buffer{})),
+		Route{Name: "errors", Match: MatchLevel(LevelError), Handler: NewTextHandler(WithWriter(&bytes.Buffer{}))},
+		Route{Name: "payments", Match: MatchAttrValue("service", "payments"), Handler: NewTextHandler(WithWriter(&bytes.Buffer{}))},
+	)
+
+	record := NewRecord(LevelError, "Charge failed")
+	decisions := router.Explain(record)
+	if len(decisions) != 1 || decisions[0].Route != "errors" || !decisions[0].Matched || !decisions[0].Stopped {
+		t.Errorf("Expected routing to stop at the errors route, got %+v", decisions)
+	}
+
+	record = NewRecord(LevelInfo, "Charge created")
+	record.Attributes.SetByDotNotation("service", "payments")
+	decisions = router.Explain(record)
+	if len(decisions) != 2 || decisions[0].Matched || !decisions[1].Matched || decisions[1].Route != "payments" {
+		t.Errorf("Expected the payments route to match, got %+v", decisions)
+	}
+
+	record = NewRecord(LevelInfo, "Charge viewed")
+	decisions = router.Explain(record)
+	if last := decisions[len(decisions)-1]; last.Route != "default" || !last.Matched {
+		t.Errorf("Expected the default route to match, got %+v", decisions)
+	}
+}
+
+func TestRouterHandlerMatchers(t *testing.T) {
+	record := NewRecord(LevelWarn, "Disk almost full")
+	record.Attributes.SetByDotNotation("disk.mount", "/var")
+	record.Attributes.SetByDotNotation("disk.tags", []string{"ssd", "raid"})
+	record.Add(Int("user.id", 42), slog.Int("user.age", 30), slog.Float64("disk.used", 0.5))
+
+	cases := []struct {
+		name    string
+		matcher RouteMatcher
+		want    bool
+	}{
+		{"level", MatchLevel(LevelWarn), true},
+		{"level below", MatchLevelBelow(LevelWarn), false},
+		{"message", MatchMessage("almost full"), true},
+		{"attr", MatchAttr("disk.mount"), true},
+		{"attr value", MatchAttrValue("disk.mount", "/tmp"), false},
+		{"attr slice value", MatchAttrValue("disk.tags", []string{"ssd", "raid"}), true},
+		{"attr slice value differs", MatchAttrValue("disk.tags", []string{"ssd"}), false},
+		{"attr Int value", MatchAttrValue("user.id", 42), true},
+		{"attr slog.Int value", MatchAttrValue("user.age", 30), true},
+		{"attr slog.Int unsigned value", MatchAttrValue("user.age", uint(30)), true},
+		{"attr slog.Float64 value", MatchAttrValue("disk.used", 0.5), true},
+		{"attr int differs", MatchAttrValue("user.id", 42.5), false},
+		{"attr number and string", MatchAttrValue("user.id", "42"), false},
+		{"attr prefix", MatchAttrPrefix("disk"), true},
+		{"attr prefix boundary", MatchAttrPrefix("dis"), false},
+		{"all", MatchAll(MatchLevel(LevelWarn), MatchAttr("disk.mount")), true},
+		{"any", MatchAny(MatchLevel(LevelError), MatchMessage("Disk")), true},
+		{"not", MatchNot(MatchMessage("Disk")), false},
+	}
+
+	for _, tc := range cases {
+		if got := tc.matcher(record); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestRouterHandlerWithAttrs(t *testing.T) {
+	routeBuf := &bytes.Buffer{}
+	router := NewRouterHandler(nil,
+		Route{Name: "all", Handler: NewJSONHandler(WithWriter(routeBuf))},
+	)
+	derived := router.WithAttrs([]slog.Attr{slog.String("service", "billing")})
+	New(derived).Info("Invoice sent")
+
+	if !strings.Contains(routeBuf.String(), `"service":"billing"`) {
+		t.Errorf("Expected attributes to reach the route handler: %s", routeBuf.String())
+	}
+	if router.Stats()["all"] != 1 {
+		t.Errorf("Expected derived routers to share stats, got %v", router.Stats())
+	}
+	if !router.Enabled(context.Background(), LevelInfo) || router.Enabled(context.Background(), LevelDebug) {
+		t.Error("Expected Enabled to follow the route handlers")
+	}
+}
@@ -0,0 +1,220 @@
+package sawmill
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	samplingLevels  = int(LevelMark-LevelTrace) + 1
+	samplingBuckets = 4096
+)
+
+// SamplingOptions configures a SamplingHandler
+type SamplingOptions struct {
+	Tick        time.Duration // Length of each sampling period
+	First       int           // Records logged per level and message in each tick before sampling starts
+	Thereafter  int           // After First, every Thereafter-th record is logged; 0 drops the rest
+	ExemptLevel Level         // Records at or above this level are never sampled
+	Summary     bool          // Emit a record reporting dropped records after each tick with drops
+}
+
+// DefaultSamplingOptions returns options that keep the first 100 records per
+// message each second and every 100th after that
+func DefaultSamplingOptions() *SamplingOptions {
+	return &SamplingOptions{
+		Tick:        time.Second,
+		First:       100,
+		Thereafter:  100,
+		ExemptLevel: LevelFatal,
+		Summary:     false,
+	}
+}
+
+// SamplingStats reports how many records a SamplingHandler passed on and dropped
+type SamplingStats struct {
+	Sampled uint64
+	Dropped uint64
+}
+
+// SamplingHandler wraps a handler and caps the volume of repeated records.
+// Records are keyed by level and message; within each tick the first records
+// for a key are passed on, then only every Thereafter-th.
+type SamplingHandler struct {
+	handler Handler
+	opts    *SamplingOptions
+	state   *samplingState
+}
+
+// samplingState is shared by a SamplingHandler and its WithAttrs/WithGroup copies
+type samplingState struct {
+	counters [samplingLevels][samplingBuckets]samplingCounter
+	sampled  atomic.Uint64
+	dropped  atomic.Uint64
+
+	tickEnd     atomic.Int64  // end of the current summary tick in unix nanoseconds
+	tickDropped atomic.Uint64 // records dropped since the last summary
+}
+
+// samplingCounter counts records for one hash bucket within a tick
+type samplingCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// NewSamplingHandler wraps handler with sampling; nil options use DefaultSamplingOptions
+func NewSamplingHandler(handler Handler, opts *SamplingOptions) *SamplingHandler {
+	if opts == nil {
+		opts = DefaultSamplingOptions()
+	}
+	if opts.Tick <= 0 {
+		opts.Tick = time.Second
+	}
+
+	return &SamplingHandler{
+		handler: handler,
+		opts:    opts,
+		state:   &samplingState{},
+	}
+}
+
+// Unwrap returns the wrapped handler
+func (h *SamplingHandler) Unwrap() Handler {
+	return h.handler
+}
+
+// Stats returns the number of records sampled and dropped so far
+func (h *SamplingHandler) Stats() SamplingStats {
+	return SamplingStats{
+		Sampled: h.state.sampled.Load(),
+		Dropped: h.state.dropped.Load(),
+	}
+}
+
+func (h *SamplingHandler) Handle(ctx context.Context, record *Record) error {
+	now := record.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	var summaryErr error
+	if h.opts.Summary {
+		summaryErr = h.rollTick(ctx, now)
+	}
+
+	if !h.sample(record, now) {
+		h.state.dropped.Add(1)
+		h.state.tickDropped.Add(1)
+		return summaryErr
+	}
+
+	h.state.sampled.Add(1)
+	return errors.Join(summaryErr, h.handler.Handle(ctx, record))
+}
+
+// sample reports whether a record should be passed on
+func (h *SamplingHandler) sample(record *Record, now time.Time) bool {
+	if record.Level >= h.opts.ExemptLevel || record.Level < LevelTrace || record.Level > LevelMark {
+		return true
+	}
+
+	bucket := samplingHash(record.Message) % samplingBuckets
+	counter := &h.state.counters[record.Level-LevelTrace][bucket]
+
+	n := counter.incCheckReset(now, h.opts.Tick)
+	if n <= uint64(h.opts.First) {
+		return true
+	}
+	return h.opts.Thereafter > 0 && (n-uint64(h.opts.First))%uint64(h.opts.Thereafter) == 0
+}
+
+// rollTick starts a new summary tick once the current one has ended,
+// emitting a summary for the records dropped during it
+func (h *SamplingHandler) rollTick(ctx context.Context, now time.Time) error {
+	end := h.state.tickEnd.Load()
+	if now.UnixNano() < end {
+		return nil
+	}
+	if !h.state.tickEnd.CompareAndSwap(end, now.Add(h.opts.Tick).UnixNano()) {
+		return nil
+	}
+	return h.emitSummary(ctx)
+}
+
+// emitSummary writes a record reporting the records dropped since the last summary
+func (h *SamplingHandler) emitSummary(ctx context.Context) error {
+	dropped := h.state.tickDropped.Swap(0)
+	if dropped == 0 {
+		return nil
+	}
+
+	summary := NewRecord(LevelWarn, "Sampling dropped records")
+	summary.Context = ctx
+	summary.Attributes.SetByDotNotation("sampling.dropped", dropped)
+	summary.Attributes.SetByDotNotation("sampling.tick", h.opts.Tick.String())
+	return h.handler.Handle(ctx, summary)
+}
+
+// incCheckReset increments the counter, resetting it when the tick has passed
+func (c *samplingCounter) incCheckReset(now time.Time, tick time.Duration) uint64 {
+	tn := now.UnixNano()
+	resetAt := c.resetAt.Load()
+	if resetAt > tn {
+		return c.count.Add(1)
+	}
+
+	c.count.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, tn+tick.Nanoseconds()) {
+		// Another goroutine reset the counter first
+		return c.count.Add(1)
+	}
+	return 1
+}
+
+// samplingHash is an allocation-free FNV-1a hash of the message
+func samplingHash(s string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	hash := uint32(offset32)
+	for i := 0; i < len(s); i++ {
+		hash ^= uint32(s[i])
+		hash *= prime32
+	}
+	return hash
+}
+
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) Handler {
+	return &SamplingHandler{handler: h.handler.WithAttrs(attrs), opts: h.opts, state: h.state}
+}
+
+func (h *SamplingHandler) WithGroup(name string) Handler {
+	return &SamplingHandler{handler: h.handler.WithGroup(name), opts: h.opts, state: h.state}
+}
+
+func (h *SamplingHandler) Enabled(ctx context.Context, level Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// NeedsSource reports whether the wrapped handler needs source information
+func (h *SamplingHandler) NeedsSource() bool {
+	return handlerNeedsSource(h.handler)
+}
+
+// Flush emits any pending summary and flushes the wrapped handler
+func (h *SamplingHandler) Flush() error {
+	var summaryErr error
+	if h.opts.Summary {
+		summaryErr = h.emitSummary(context.Background())
+	}
+	return errors.Join(summaryErr, flushHandler(h.handler))
+}
+
+// Close closes the wrapped handler
+func (h *SamplingHandler) Close() error {
+	return closeHandler(h.handler)
+}
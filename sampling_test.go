@@ -0,0 +1,96 @@
+package sawmill
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewSamplingHandler(NewTextHandler(WithWriter(buf)), &SamplingOptions{
+		Tick:        time.Minute,
+		First:       3,
+		Thereafter:  5,
+		ExemptLevel: LevelError,
+	})
+	logger := New(handler)
+
+	for i := 0; i < 20; i++ {
+		logger.Info("Hot path")
+	}
+	logger.Info("Other message")
+	logger.Error("Always logged")
+	logger.Error("Always logged")
+
+	// First 3, then the 8th, 13th and 18th
+	if count := strings.Count(buf.String(), "Hot path"); count != 6 {
+		t.Errorf("Expected 6 sampled records, got %d", count)
+	}
+	if !strings.Contains(buf.String(), "Other message") {
+		t.Error("Expected a different message to have its own counter")
+	}
+	if count := strings.Count(buf.String(), "Always logged"); count != 2 {
+		t.Errorf("Expected exempt level to bypass sampling, got %d", count)
+	}
+
+	stats := handler.Stats()
+	if stats.Sampled != 9 || stats.Dropped != 14 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestSamplingHandlerTickReset(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewSamplingHandler(NewTextHandler(WithWriter(buf)), &SamplingOptions{
+		Tick:        20 * time.Millisecond,
+		First:       1,
+		ExemptLevel: LevelFatal,
+	})
+	logger := New(handler)
+
+	logger.Info("Tick message")
+	logger.Info("Tick message")
+	time.Sleep(30 * time.Millisecond)
+	logger.Info("Tick message")
+
+	if count := strings.Count(buf.String(), "Tick message"); count != 2 {
+		t.Errorf("Expected counter to reset after the tick, got %d records", count)
+	}
+}
+
+func TestSamplingHandlerSummaryWithMultiHandler(t *testing.T) {
+	buf1, buf2 := &bytes.Buffer{}, &bytes.Buffer{}
+	opts := DefaultSamplingOptions()
+	opts.First = 1
+	opts.Thereafter = 0
+	opts.Summary = true
+
+	handler := NewSamplingHandler(NewMultiHandler(
+		NewJSONHandler(WithWriter(buf1)),
+		NewTextHandler(WithWriter(buf2)),
+	), opts)
+	logger := New(handler).WithDot("component", "worker")
+
+	for i := 0; i < 5; i++ {
+		logger.Info("Polling")
+	}
+
+	if err := logger.Flush(); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+
+	if count := strings.Count(buf1.String(), `"message":"Polling"`); count != 1 {
+		t.Errorf("Expected 1 sampled record, got %d: %s", count, buf1.String())
+	}
+	if !strings.Contains(buf1.String(), `"sampling.dropped":4`) {
+		t.Errorf("Expected summary of dropped records: %s", buf1.String())
+	}
+	if !strings.Contains(buf2.String(), "Sampling dropped records") {
+		t.Errorf("Expected summary in every child handler: %s", buf2.String())
+	}
+	if handler.Unwrap() == nil {
+		t.Error("Expected Unwrap to return the wrapped handler")
+	}
+}
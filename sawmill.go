@@ -2,6 +2,8 @@
 // with nested key-value support, dynamic callbacks, flexible output formatting, and color syntax highlighting.
 package sawmill
 
+import "context"
+
 // DefaultLogger is the global default logger instance
 var DefaultLogger Logger
 
@@ -34,12 +36,12 @@ func Error(msg string, args ...interface{}) {
 	DefaultLogger.Error(msg, args...)
 }
 
-// Fatal logs a message at fatal level
+// Fatal logs a message at fatal level and exits the process
 func Fatal(msg string, args ...interface{}) {
 	DefaultLogger.Fatal(msg, args...)
 }
 
-// Panic logs a message at panic level and panics
+// Panic logs a message at panic level and panics with a *PanicError
 func Panic(msg string, args ...interface{}) {
 	DefaultLogger.Panic(msg, args...)
 }
@@ -49,6 +51,61 @@ func Mark(msg string, args ...interface{}) {
 	DefaultLogger.Mark(msg, args...)
 }
 
+// TraceContext logs a message at trace level with the given context
+func TraceContext(ctx context.Context, msg string, args ...interface{}) {
+	DefaultLogger.TraceContext(ctx, msg, args...)
+}
+
+// DebugContext logs a message at debug level with the given context
+func DebugContext(ctx context.Context, msg string, args ...interface{}) {
+	DefaultLogger.DebugContext(ctx, msg, args...)
+}
+
+// InfoContext logs a message at info level with the given context
+func InfoContext(ctx context.Context, msg string, args ...interface{}) {
+	DefaultLogger.InfoContext(ctx, msg, args...)
+}
+
+// WarnContext logs a message at warn level with the given context
+func WarnContext(ctx context.Context, msg string, args ...interface{}) {
+	DefaultLogger.WarnContext(ctx, msg, args...)
+}
+
+// ErrorContext logs a message at error level with the given context
+func ErrorContext(ctx context.Context, msg string, args ...interface{}) {
+	DefaultLogger.ErrorContext(ctx, msg, args...)
+}
+
+// FatalContext logs a message at fatal level with the given context and exits the process
+func FatalContext(ctx context.Context, msg string, args ...interface{}) {
+	DefaultLogger.FatalContext(ctx, msg, args...)
+}
+
+// PanicContext logs a message at panic level with the given context and panics with a *PanicError
+func PanicContext(ctx context.Context, msg string, args ...interface{}) {
+	DefaultLogger.PanicContext(ctx, msg, args...)
+}
+
+// MarkContext logs a message at mark level with the given context
+func MarkContext(ctx context.Context, msg string, args ...interface{}) {
+	DefaultLogger.MarkContext(ctx, msg, args...)
+}
+
+// Start begins timing an operation with the default logger
+func Start(operation string, args ...interface{}) func(err error) {
+	return DefaultLogger.Start(operation, args...)
+}
+
+// Flush writes out records buffered by the default logger's handlers
+func Flush() error {
+	return DefaultLogger.Flush()
+}
+
+// Shutdown flushes and closes the default logger's handlers
+func Shutdown(ctx context.Context) error {
+	return DefaultLogger.Shutdown(ctx)
+}
+
 // WithNested returns a logger with nested attributes
 func WithNested(keyPath []string, value interface{}) Logger {
 	return DefaultLogger.WithNested(keyPath, value)
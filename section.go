@@ -0,0 +1,133 @@
+package sawmill
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Section is a named phase of work opened by Logger.Section. It is a Logger
+// whose records are counted, carry the section path under section.path and
+// are indented in text output. Sections opened from a Section are nested.
+type Section struct {
+	Logger
+	parent *logger
+	state  *sectionState
+	ended  atomic.Bool
+}
+
+// sectionState is shared by a section's logger and the loggers derived from it
+type sectionState struct {
+	name    string
+	path    string // Names of the enclosing sections and this one, joined with "/"
+	depth   int
+	start   time.Time
+	id      string
+	parent  *sectionState
+	records atomic.Uint64
+}
+
+// Section writes a begin mark for name and returns the section. End writes
+// the matching end mark with the elapsed time and the number of records
+// logged inside the section; both marks share an OutputID.
+//
+// Example usage:
+//
+//	section := logger.Section("data import")
+//	section.Info("Reading file", "path", path)
+//	parse := section.Section("parse") // section.path "data import/parse"
+//	parse.End()
+//	section.End()
+func (l *logger) Section(name string, args ...interface{}) *Section {
+	state := &sectionState{
+		name:   name,
+		path:   name,
+		depth:  1,
+		start:  time.Now(),
+		id:     generateOutputID(),
+		parent: l.section,
+	}
+	if l.section != nil {
+		state.path = l.section.path + "/" + name
+		state.depth = l.section.depth + 1
+	}
+
+	inner := l.clone()
+	inner.section = state
+	l.logSectionMark(state, name+" started", args)
+
+	return &Section{Logger: inner, parent: l, state: state}
+}
+
+// End writes the end mark of the section. Only the first call writes a record.
+func (s *Section) End(args ...interface{}) {
+	if s.ended.Swap(true) {
+		return
+	}
+
+	args = append(args[:len(args):len(args)],
+		Duration("section.elapsed", s.Elapsed()),
+		Uint64("section.records", s.Records()),
+	)
+	s.parent.logSectionMark(s.state, s.state.name+" completed", args)
+}
+
+// Path returns the names of the enclosing sections and this one, joined with "/"
+func (s *Section) Path() string {
+	return s.state.path
+}
+
+// Elapsed returns the time since the section began
+func (s *Section) Elapsed() time.Duration {
+	return time.Since(s.state.start)
+}
+
+// Records returns the number of records logged inside the section, including
+// nested sections; begin and end marks are not counted
+func (s *Section) Records() uint64 {
+	return s.state.records.Load()
+}
+
+// enterSection marks a record as logged inside the logger's section
+func (l *logger) enterSection(record *Record) {
+	if l.section == nil {
+		return
+	}
+
+	record.sectionDepth = l.section.depth
+	record.Attributes.SetByDotNotation("section.path", l.section.path)
+	for section := l.section; section != nil; section = section.parent {
+		section.records.Add(1)
+	}
+}
+
+// logSectionMark writes a begin or end mark of state at the depth of its
+// parent. Marks do not count toward the records of any section.
+func (l *logger) logSectionMark(state *sectionState, msg string, args []interface{}) {
+	marker := l.clone()
+	marker.section = nil
+	marker.logCorrelated(LevelMark, msg, state.id, args, func(record *Record) {
+		record.sectionDepth = state.depth - 1
+		record.Attributes.SetByDotNotation("section.path", state.path)
+	})
+}
+
+// indentLines indents every line of text by depth levels
+func indentLines(text string, depth int) string {
+	if depth <= 0 {
+		return text
+	}
+
+	indent := strings.Repeat("  ", depth)
+	lines := strings.SplitAfter(text, "\n")
+
+	var builder strings.Builder
+	builder.Grow(len(text) + len(lines)*len(indent))
+	for _, line := range lines {
+		if line != "" && line != "\n" {
+			builder.WriteString(indent)
+		}
+		builder.WriteString(line)
+	}
+	return builder.String()
+}
@@ -0,0 +1,173 @@
+package sawmill
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSectionMarksAndCounts(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf), WithLevel(LevelDebug)))
+
+	section := logger.Section("data import", "file", "users.csv")
+	section.Info("Reading file")
+	section.WithDot("batch", 1).Debug("Batch loaded")
+	logger.Info("Outside the section")
+	section.End()
+	section.End()
+
+	entries := decodeLines(t, buf)
+	if len(entries) != 5 {
+		t.Fatalf("Expected 5 records, got %d: %s", len(entries), buf.String())
+	}
+
+	begin, end := entries[0], entries[4]
+	if begin["message"] != "data import started" || begin["level"] != "MARK" {
+		t.Errorf("Unexpected begin mark: %v", begin)
+	}
+	if end["message"] != "data import completed" || end["level"] != "MARK" {
+		t.Errorf("Unexpected end mark: %v", end)
+	}
+	if begin["output_id"] == nil || begin["output_id"] != end["output_id"] {
+		t.Errorf("Expected begin and end marks to share an OutputID: %v / %v", begin["output_id"], end["output_id"])
+	}
+
+	endAttrs := end["attributes"].(map[string]interface{})
+	if endAttrs["section.records"] != float64(2) {
+		t.Errorf("Expected 2 records inside the section, got %v", endAttrs["section.records"])
+	}
+	if _, ok := endAttrs["section.elapsed"].(float64); !ok {
+		t.Errorf("Expected the elapsed time: %v", endAttrs)
+	}
+	if attrs := begin["attributes"].(map[string]interface{}); attrs["file"] != "users.csv" || attrs["section.path"] != "data import" {
+		t.Errorf("Expected the section arguments and path on the begin mark: %v", attrs)
+	}
+	if attrs := entries[1]["attributes"].(map[string]interface{}); attrs["section.path"] != "data import" {
+		t.Errorf("Expected section.path on records inside the section: %v", attrs)
+	}
+	if _, ok := entries[3]["attributes"]; ok {
+		t.Errorf("Expected no section attributes outside the section: %v", entries[3])
+	}
+}
+
+func TestSectionNesting(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf), WithSourceInfo(false)))
+
+	outer := logger.Section("import")
+	outer.Info("Reading")
+	inner := outer.Section("parse")
+	inner.Info("Parsed")
+	inner.End()
+	outer.Info("Done")
+	outer.End()
+
+	if inner.Path() != "import/parse" {
+		t.Errorf("Expected nested path, got %q", inner.Path())
+	}
+	if inner.Records() != 1 {
+		t.Errorf("Expected 1 record in the inner section, got %d", inner.Records())
+	}
+	// Records of the inner section count toward the outer section; marks do not
+	if outer.Records() != 3 {
+		t.Errorf("Expected 3 records in the outer section, got %d", outer.Records())
+	}
+	if !strings.Contains(buf.String(), `"section.records":3`) || !strings.Contains(buf.String(), `"section.records":1`) {
+		t.Errorf("Expected the end marks to report the record counts: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `"message":"Parsed","level":"INFO","attributes":{"section.path":"import/parse"}`) {
+		t.Errorf("Expected the nested section path: %s", buf.String())
+	}
+}
+
+func TestSectionTextIndentation(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewTextHandler(WithWriter(buf), WithSourceInfo(false)))
+
+	outer := logger.Section("import")
+	outer.Info("Top")
+	inner := outer.Section("parse")
+	inner.Info("Nested")
+	inner.End()
+	outer.End()
+
+	lines := strings.Split(buf.String(), "\n")
+	find := func(text string) string {
+		for _, line := range lines {
+			if strings.Contains(line, text) {
+				return line
+			}
+		}
+		t.Fatalf("Expected %q in output: %s", text, buf.String())
+		return ""
+	}
+
+	if line := find("import started"); !strings.HasPrefix(line, " MARKED") {
+		t.Errorf("Expected the outer begin mark unindented: %q", line)
+	}
+	if line := find("[INFO] Top"); !strings.HasPrefix(line, "  2") {
+		t.Errorf("Expected records in the outer section indented once: %q", line)
+	}
+	if line := find("parse started"); !strings.HasPrefix(line, "   MARKED") {
+		t.Errorf("Expected the inner begin mark indented once: %q", line)
+	}
+	if line := find("[INFO] Nested"); !strings.HasPrefix(line, "    2") {
+		t.Errorf("Expected records in the inner section indented twice: %q", line)
+	}
+}
+
+func TestSectionKeyValueMarks(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewKeyValueHandler(WithWriter(buf), WithSourceInfo(false)))
+
+	outer := logger.Section("outer")
+	inner := outer.Section("inner")
+	inner.Info("Nested")
+	inner.End()
+	outer.End()
+
+	lines := strings.Split(buf.String(), "\n")
+	find := func(text string) string {
+		for _, line := range lines {
+			if strings.Contains(line, text) {
+				return line
+			}
+		}
+		t.Fatalf("Expected %q in output: %s", text, buf.String())
+		return ""
+	}
+
+	if line := find("outer started"); !strings.HasPrefix(line, " MARKED") {
+		t.Errorf("Expected the outer begin mark unindented: %q", line)
+	}
+	if line := find("outer completed"); !strings.HasPrefix(line, " MARKED") {
+		t.Errorf("Expected the outer end mark unindented: %q", line)
+	}
+	if line := find("inner started"); !strings.HasPrefix(line, "   MARKED") {
+		t.Errorf("Expected the inner begin mark indented once: %q", line)
+	}
+	if line := find("message=Nested"); !strings.HasPrefix(line, "    timestamp=") {
+		t.Errorf("Expected records in the inner section indented twice: %q", line)
+	}
+}
+
+func TestSectionIndentationWithHandlerAttrs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewTextHandler(WithWriter(buf), WithSourceInfo(false)).WithAttrs([]slog.Attr{slog.String("service", "api")})
+	logger := New(handler)
+
+	section := logger.Section("import")
+	section.Info("Inside")
+	section.End()
+
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.Contains(line, "[INFO] Inside") && !strings.HasPrefix(line, "  2") {
+			t.Errorf("Expected records in the section indented with handler attributes: %q", line)
+		}
+	}
+	if !strings.Contains(buf.String(), "[INFO] Inside") {
+		t.Errorf("Expected the section record: %s", buf.String())
+	}
+}
@@ -0,0 +1,53 @@
+package sawmill
+
+import (
+	"context"
+	"errors"
+)
+
+// flushHandler flushes a handler if it implements Flusher
+func flushHandler(handler Handler) error {
+	if flusher, ok := handler.(Flusher); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// closeHandler closes a handler if it implements Closer
+func closeHandler(handler Handler) error {
+	if closer, ok := handler.(Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// shutdownHandler drains and flushes a handler tree, then closes it
+func shutdownHandler(handler Handler) error {
+	if handler == nil {
+		return nil
+	}
+	return errors.Join(flushHandler(handler), closeHandler(handler))
+}
+
+// Flush writes out records buffered anywhere in the logger's handler tree
+func (l *logger) Flush() error {
+	return flushHandler(l.Handler())
+}
+
+// Shutdown flushes and closes the logger's handler tree. It returns ctx.Err()
+// if the context ends first; the shutdown keeps running in the background.
+func (l *logger) Shutdown(ctx context.Context) error {
+	handler := l.Handler()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- shutdownHandler(handler)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,91 @@
+package sawmill
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoggerShutdown(t *testing.T) {
+	dir := t.TempDir()
+
+	fileBuffer, err := NewFileBuffer(filepath.Join(dir, "app.log"), 64*1024, 0, false)
+	if err != nil {
+		t.Fatalf("NewFileBuffer failed: %v", err)
+	}
+	rotatingBuffer, err := NewRotatingFileBuffer(filepath.Join(dir, "rotating.log"), 1024*1024, 2, 64*1024)
+	if err != nil {
+		t.Fatalf("NewRotatingFileBuffer failed: %v", err)
+	}
+
+	fileHandler := NewBaseHandler(NewTextFormatter(), fileBuffer, LevelInfo)
+	logger := New(NewMultiHandler(
+		fileHandler,
+		fileHandler.WithGroup("shared"),
+		NewBaseHandler(NewJSONFormatter(), rotatingBuffer, LevelInfo),
+	))
+
+	logger.Info("Buffered message")
+
+	data, _ := os.ReadFile(filepath.Join(dir, "app.log"))
+	if len(data) != 0 {
+		t.Fatalf("Expected output to be buffered before Flush: %q", data)
+	}
+
+	if err := logger.Flush(); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, "app.log"))
+	if !strings.Contains(string(data), "Buffered message") {
+		t.Errorf("Expected Flush to write buffered output: %q", data)
+	}
+
+	logger.Info("Final message")
+	if err := logger.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+
+	for _, name := range []string{"app.log", "rotating.log"} {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		if !strings.Contains(string(data), "Final message") {
+			t.Errorf("Expected %s to be flushed on shutdown: %q", name, data)
+		}
+	}
+
+	if _, err := fileBuffer.Write([]byte("late")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Expected writes after shutdown to fail, got %v", err)
+	}
+	if err := logger.Shutdown(context.Background()); err != nil {
+		t.Errorf("Expected repeated Shutdown to succeed, got %v", err)
+	}
+}
+
+// blockingHandler blocks Flush until released
+type blockingHandler struct {
+	*BaseHandler
+	release chan struct{}
+}
+
+func (h *blockingHandler) Flush() error {
+	<-h.release
+	return nil
+}
+
+func TestLoggerShutdownHonorsContext(t *testing.T) {
+	handler := &blockingHandler{
+		BaseHandler: NewBaseHandler(NewTextFormatter(), NewMemoryBuffer(0), LevelInfo),
+		release:     make(chan struct{}),
+	}
+	defer close(handler.release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := New(handler).Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context deadline error, got %v", err)
+	}
+}
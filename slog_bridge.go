@@ -0,0 +1,246 @@
+package sawmill
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+)
+
+// slog levels for sawmill levels without a standard slog equivalent
+const (
+	SlogLevelTrace = slog.Level(-8)
+	SlogLevelFatal = slog.Level(12)
+	SlogLevelPanic = slog.Level(16)
+	SlogLevelMark  = slog.Level(20)
+)
+
+// ToSlogLevel converts a sawmill level to the matching slog level
+func ToSlogLevel(level Level) slog.Level {
+	switch {
+	case level <= LevelTrace:
+		return SlogLevelTrace
+	case level == LevelDebug:
+		return slog.LevelDebug
+	case level == LevelInfo:
+		return slog.LevelInfo
+	case level == LevelWarn:
+		return slog.LevelWarn
+	case level == LevelError:
+		return slog.LevelError
+	case level == LevelFatal:
+		return SlogLevelFatal
+	case level == LevelPanic:
+		return SlogLevelPanic
+	default:
+		return SlogLevelMark
+	}
+}
+
+// FromSlogLevel converts a slog level to the closest sawmill level at or below it
+func FromSlogLevel(level slog.Level) Level {
+	switch {
+	case level < slog.LevelDebug:
+		return LevelTrace
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelWarn:
+		return LevelInfo
+	case level < slog.LevelError:
+		return LevelWarn
+	case level < SlogLevelFatal:
+		return LevelError
+	case level < SlogLevelPanic:
+		return LevelFatal
+	case level < SlogLevelMark:
+		return LevelPanic
+	default:
+		return LevelMark
+	}
+}
+
+// SlogHandler adapts a sawmill Handler to the slog.Handler interface
+type SlogHandler struct {
+	handler Handler
+	attrs   *FlatAttributes
+	groups  []string
+}
+
+// NewSlogHandler creates a slog.Handler that writes through the given sawmill handler
+func NewSlogHandler(handler Handler) *SlogHandler {
+	return &SlogHandler{
+		handler: handler,
+		attrs:   NewFlatAttributes(),
+		groups:  make([]string, 0),
+	}
+}
+
+// NewSlogLogger creates a *slog.Logger that writes through the given sawmill handler
+//
+// Example usage:
+//
+//	slog.SetDefault(sawmill.NewSlogLogger(sawmill.NewJSONHandler()))
+func NewSlogLogger(handler Handler) *slog.Logger {
+	return slog.New(NewSlogHandler(handler))
+}
+
+// Handler returns the wrapped sawmill handler
+func (h *SlogHandler) Handler() Handler {
+	return h.handler
+}
+
+// Enabled implements slog.Handler
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, FromSlogLevel(level))
+}
+
+// Handle implements slog.Handler
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	record := NewRecordFromPool(FromSlogLevel(r.Level), r.Message)
+	if !r.Time.IsZero() {
+		record.Time = r.Time
+	}
+	record.Context = ctx
+	record.PC = r.PC
+
+	record.Attributes.Merge(h.attrs)
+	r.Attrs(func(attr slog.Attr) bool {
+		record.Attributes.SetAttr(h.groups, attr)
+		return true
+	})
+
+	err := h.handler.Handle(ctx, record)
+
+	// Return record to pool after use
+	if !handlerRetainsRecords(h.handler) {
+		ReturnRecordToPool(record)
+	}
+
+	return err
+}
+
+// WithAttrs implements slog.Handler
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	newHandler := h.clone()
+	for _, attr := range attrs {
+		newHandler.attrs.SetAttr(h.groups, attr)
+	}
+	return newHandler
+}
+
+// WithGroup implements slog.Handler
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	newHandler := h.clone()
+	newHandler.groups = append(newHandler.groups, name)
+	return newHandler
+}
+
+func (h *SlogHandler) clone() *SlogHandler {
+	newGroups := make([]string, len(h.groups))
+	copy(newGroups, h.groups)
+
+	return &SlogHandler{
+		handler: h.handler,
+		attrs:   h.attrs.Clone(),
+		groups:  newGroups,
+	}
+}
+
+// SlogWrapHandler adapts a slog.Handler to the sawmill Handler interface
+type SlogWrapHandler struct {
+	handler slog.Handler
+}
+
+// NewSlogWrapHandler creates a sawmill handler that writes through the given slog.Handler
+func NewSlogWrapHandler(handler slog.Handler) *SlogWrapHandler {
+	return &SlogWrapHandler{handler: handler}
+}
+
+// SlogHandler returns the wrapped slog handler
+func (h *SlogWrapHandler) SlogHandler() slog.Handler {
+	return h.handler
+}
+
+func (h *SlogWrapHandler) Handle(ctx context.Context, record *Record) error {
+	record.ResolveValuers()
+	r := slog.NewRecord(record.Time, ToSlogLevel(record.Level), record.Message, record.PC)
+	if record.LoggerName != "" {
+		r.AddAttrs(slog.String("logger", record.LoggerName))
+	}
+	if outputID := record.correlatedOutputID(); outputID != "" {
+		r.AddAttrs(slog.String("output_id", outputID))
+	}
+	r.AddAttrs(flatAttributesToSlog(record.Attributes)...)
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *SlogWrapHandler) WithAttrs(attrs []slog.Attr) Handler {
+	return &SlogWrapHandler{handler: h.handler.WithAttrs(attrs)}
+}
+
+func (h *SlogWrapHandler) WithGroup(name string) Handler {
+	return &SlogWrapHandler{handler: h.handler.WithGroup(name)}
+}
+
+func (h *SlogWrapHandler) Enabled(ctx context.Context, level Level) bool {
+	return h.handler.Enabled(ctx, ToSlogLevel(level))
+}
+
+// slogNode is a node in the group tree built from dot notation keys
+type slogNode struct {
+	value    interface{}
+	hasValue bool
+	children map[string]*slogNode
+}
+
+// flatAttributesToSlog converts dot notation keys into nested slog groups
+func flatAttributesToSlog(attrs *FlatAttributes) []slog.Attr {
+	if attrs == nil || attrs.IsEmpty() {
+		return nil
+	}
+
+	root := &slogNode{children: make(map[string]*slogNode)}
+	attrs.Walk(func(path []string, value interface{}) {
+		node := root
+		for _, part := range path {
+			child, exists := node.children[part]
+			if !exists {
+				child = &slogNode{children: make(map[string]*slogNode)}
+				node.children[part] = child
+			}
+			node = child
+		}
+		node.value = value
+		node.hasValue = true
+	})
+
+	return root.attrs()
+}
+
+func (n *slogNode) attrs() []slog.Attr {
+	keys := make([]string, 0, len(n.children))
+	for key := range n.children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]slog.Attr, 0, len(keys))
+	for _, key := range keys {
+		child := n.children[key]
+		if child.hasValue {
+			result = append(result, slog.Any(key, child.value))
+		}
+		if len(child.children) > 0 {
+			members := child.attrs()
+			result = append(result, slog.Attr{Key: key, Value: slog.GroupValue(members...)})
+		}
+	}
+	return result
+}
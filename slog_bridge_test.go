@@ -0,0 +1,122 @@
+package sawmill
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogLevelMapping(t *testing.T) {
+	levels := []Level{LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError, LevelFatal, LevelPanic, LevelMark}
+
+	for _, level := range levels {
+		if got := FromSlogLevel(ToSlogLevel(level)); got != level {
+			t.Errorf("level %s did not round-trip, got %s", levelToString(level), levelToString(got))
+		}
+	}
+
+	if ToSlogLevel(LevelInfo) != slog.LevelInfo || ToSlogLevel(LevelError) != slog.LevelError {
+		t.Error("Expected standard levels to map to their slog equivalents")
+	}
+	if FromSlogLevel(slog.LevelWarn+2) != LevelWarn {
+		t.Error("Expected intermediate slog levels to map to the level below")
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewSlogLogger(NewJSONHandler(WithDestination(NewWriterDestination(buf))))
+
+	logger.Info("Request handled",
+		"status", 200,
+		slog.Group("request", slog.String("method", "GET"), slog.Group("user", "id", 42)),
+	)
+
+	output := buf.String()
+	if !strings.Contains(output, `"message":"Request handled"`) {
+		t.Errorf("Expected message in output: %s", output)
+	}
+	if !strings.Contains(output, `"level":"INFO"`) {
+		t.Errorf("Expected INFO level in output: %s", output)
+	}
+	for _, expected := range []string{`"status":200`, `"request.method":"GET"`, `"request.user.id":42`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %s in output: %s", expected, output)
+		}
+	}
+}
+
+func TestSlogHandlerWithGroupAndAttrs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewSlogLogger(NewJSONHandler(WithDestination(NewWriterDestination(buf))))
+
+	logger.With("service", "api").WithGroup("http").With("port", 8080).Info("Started", "tls", true)
+
+	output := buf.String()
+	for _, expected := range []string{`"service":"api"`, `"http.port":8080`, `"http.tls":true`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %s in output: %s", expected, output)
+		}
+	}
+}
+
+func TestSlogHandlerEnabled(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewSlogLogger(NewJSONHandler(WithDestination(NewWriterDestination(buf)), WithLevel(LevelWarn)))
+
+	logger.Info("Filtered message")
+	if buf.Len() != 0 {
+		t.Errorf("Expected info message to be filtered: %s", buf.String())
+	}
+
+	logger.Error("Error message")
+	if !strings.Contains(buf.String(), `"level":"ERROR"`) {
+		t.Errorf("Expected error message in output: %s", buf.String())
+	}
+}
+
+func TestSlogWrapHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	slogHandler := slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: SlogLevelTrace})
+	logger := New(NewSlogWrapHandler(slogHandler))
+
+	logger.WithDot("user.profile.name", "Alice").Debug("Profile loaded", "count", 3)
+
+	output := buf.String()
+	if !strings.Contains(output, `"level":"DEBUG"`) {
+		t.Errorf("Expected DEBUG level in output: %s", output)
+	}
+	if !strings.Contains(output, `"user":{"profile":{"name":"Alice"}}`) {
+		t.Errorf("Expected dot paths to become nested groups: %s", output)
+	}
+	if !strings.Contains(output, `"count":3`) {
+		t.Errorf("Expected args in output: %s", output)
+	}
+}
+
+func TestSlogWrapHandlerEnabled(t *testing.T) {
+	slogHandler := slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn})
+	handler := NewSlogWrapHandler(slogHandler)
+
+	if handler.Enabled(context.Background(), LevelInfo) {
+		t.Error("Expected info level to be disabled")
+	}
+	if !handler.Enabled(context.Background(), LevelFatal) {
+		t.Error("Expected fatal level to be enabled")
+	}
+}
+
+func TestHandlerWithAttrsGroupValue(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewJSONHandler(WithDestination(NewWriterDestination(buf))).
+		WithAttrs([]slog.Attr{slog.Group("db", slog.String("name", "users"), slog.Int("pool", 4))})
+
+	New(handler).Info("Connected")
+
+	output := buf.String()
+	if !strings.Contains(output, `"db.name":"users"`) || !strings.Contains(output, `"db.pool":4`) {
+		t.Errorf("Expected group attributes flattened to dot paths: %s", output)
+	}
+}
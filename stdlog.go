@@ -0,0 +1,120 @@
+package sawmill
+
+import (
+	"context"
+	"log"
+	"strings"
+)
+
+// StdLogRule assigns a level to standard library log lines that start with Prefix
+type StdLogRule struct {
+	Prefix string // Line prefix, matched after the log.Logger's own prefix and flags are removed
+	Level  Level  // Level of matching lines; zero uses the level of unmatched lines
+	Trim   bool   // Remove the prefix and following spaces from the message
+}
+
+// StdLogOptions configures RedirectStdLog
+type StdLogOptions struct {
+	Level Level        // Level of lines that match no rule; zero uses LevelInfo
+	Rules []StdLogRule // Checked in order; the first matching rule wins. Empty uses DefaultStdLogRules
+}
+
+// DefaultStdLogRules returns rules for common level tags and for the TLS
+// handshake errors net/http logs for every failed client connection
+func DefaultStdLogRules() []StdLogRule {
+	return []StdLogRule{
+		{Prefix: "http: TLS handshake error", Level: LevelDebug},
+		{Prefix: "[TRACE]", Level: LevelTrace, Trim: true},
+		{Prefix: "[DEBUG]", Level: LevelDebug, Trim: true},
+		{Prefix: "[INFO]", Level: LevelInfo, Trim: true},
+		{Prefix: "[WARN]", Level: LevelWarn, Trim: true},
+		{Prefix: "[WARNING]", Level: LevelWarn, Trim: true},
+		{Prefix: "[ERROR]", Level: LevelError, Trim: true},
+	}
+}
+
+// DefaultStdLogOptions returns options that log unmatched lines at Info with the default rules
+func DefaultStdLogOptions() *StdLogOptions {
+	return &StdLogOptions{
+		Level: LevelInfo,
+		Rules: DefaultStdLogRules(),
+	}
+}
+
+// NewStdLogger returns a *log.Logger that writes each line to logger at
+// level, or at the level of the first matching rule. Without rules,
+// DefaultStdLogRules are used, and a zero level uses LevelInfo. Records
+// report the source of the log call.
+//
+// Example usage:
+//
+//	srv := &http.Server{ErrorLog: sawmill.NewStdLogger(logger, sawmill.LevelError)}
+func NewStdLogger(logger Logger, level Level, rules ...StdLogRule) *log.Logger {
+	return log.New(newStdLogWriter(logger, level, rules), "", 0)
+}
+
+// RedirectStdLog sends the output of the standard library's default logger
+// to logger and returns a function that restores the previous output,
+// prefix and flags. A nil opts uses DefaultStdLogOptions; unset fields use
+// their defaults as in NewStdLogger.
+//
+// Example usage:
+//
+//	restore := sawmill.RedirectStdLog(logger, nil)
+//	defer restore()
+func RedirectStdLog(logger Logger, opts *StdLogOptions) func() {
+	if opts == nil {
+		opts = DefaultStdLogOptions()
+	}
+
+	std := log.Default()
+	output, prefix, flags := std.Writer(), std.Prefix(), std.Flags()
+
+	std.SetOutput(newStdLogWriter(logger, opts.Level, opts.Rules))
+	std.SetPrefix("")
+	std.SetFlags(0)
+
+	return func() {
+		std.SetOutput(output)
+		std.SetPrefix(prefix)
+		std.SetFlags(flags)
+	}
+}
+
+// stdLogWriter turns lines written by a log.Logger into records
+type stdLogWriter struct {
+	logger Logger
+	level  Level
+	rules  []StdLogRule
+}
+
+// newStdLogWriter creates a writer, using LevelInfo for a zero level and
+// DefaultStdLogRules when there are no rules
+func newStdLogWriter(logger Logger, level Level, rules []StdLogRule) *stdLogWriter {
+	if len(rules) == 0 {
+		rules = DefaultStdLogRules()
+	}
+	return &stdLogWriter{logger: logger, level: levelOr(level, LevelInfo), rules: rules}
+}
+
+// Write implements io.Writer; log.Logger calls it once per line
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimSuffix(string(p), "\n")
+	level := w.level
+	for _, rule := range w.rules {
+		if strings.HasPrefix(msg, rule.Prefix) {
+			level = levelOr(rule.Level, w.level)
+			if rule.Trim {
+				msg = strings.TrimLeft(msg[len(rule.Prefix):], " ")
+			}
+			break
+		}
+	}
+
+	record := NewRecordFromPool(level, msg)
+	if handlerNeedsSource(w.logger.Handler()) {
+		record.PC = callerPC(0)
+	}
+	w.logger.LogRecord(context.Background(), record)
+	return len(p), nil
+}
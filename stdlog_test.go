@@ -0,0 +1,152 @@
+package sawmill
+
+import (
+	"bytes"
+	"log"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNewStdLoggerLevels(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf), WithLevel(LevelDebug)))
+	std := NewStdLogger(logger, LevelInfo)
+
+	tests := []struct {
+		line    string
+		level   string
+		message string
+	}{
+		{"plain line", "INFO", "plain line"},
+		{"[WARN] disk almost full", "WARN", "disk almost full"},
+		{"[ERROR]   write failed", "ERROR", "write failed"},
+		{"http: TLS handshake error from 10.0.0.1:5555: EOF", "DEBUG", "http: TLS handshake error from 10.0.0.1:5555: EOF"},
+	}
+
+	for _, tt := range tests {
+		buf.Reset()
+		std.Println(tt.line)
+
+		output := buf.String()
+		if !strings.Contains(output, `"level":"`+tt.level+`"`) || !strings.Contains(output, `"message":"`+tt.message+`"`) {
+			t.Errorf("Expected %s %q for %q, got %s", tt.level, tt.message, tt.line, output)
+		}
+	}
+}
+
+func TestNewStdLoggerCustomRules(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf)))
+	std := NewStdLogger(logger, LevelWarn, StdLogRule{Prefix: "audit:", Level: LevelMark, Trim: true})
+
+	std.Print("audit: user deleted")
+	std.Print("[ERROR] not a rule anymore")
+
+	output := buf.String()
+	if !strings.Contains(output, `"message":"user deleted","level":"MARK"`) {
+		t.Errorf("Expected the custom rule to apply: %s", output)
+	}
+	if !strings.Contains(output, `"message":"[ERROR] not a rule anymore","level":"WARN"`) {
+		t.Errorf("Expected custom rules to replace the defaults: %s", output)
+	}
+}
+
+func TestHTTPErrorLogTLSNoise(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewTextHandler(WithWriter(buf)))
+
+	logger.HTTPErrorLog().Printf("http: TLS handshake error from %s: EOF", "10.0.0.1:5555")
+	if buf.Len() != 0 {
+		t.Errorf("Expected TLS handshake errors below Info to be dropped: %s", buf.String())
+	}
+
+	logger.HTTPErrorLog().Print("http: panic serving 10.0.0.1:5555: boom")
+	if !strings.Contains(buf.String(), "ERROR") {
+		t.Errorf("Expected other errors at Error: %s", buf.String())
+	}
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf), WithSourceInfo(true)))
+
+	output, prefix := log.Writer(), log.Prefix()
+	defer func() {
+		log.SetOutput(output)
+		log.SetPrefix(prefix)
+	}()
+
+	previous := &bytes.Buffer{}
+	log.SetOutput(previous)
+	log.SetPrefix("app: ")
+
+	restore := RedirectStdLog(logger, nil)
+	_, _, line, _ := runtime.Caller(0)
+	log.Printf("[WARN] cache miss rate %d%%", 40)
+
+	written := buf.String()
+	if !strings.Contains(written, `"message":"cache miss rate 40%","level":"WARN"`) {
+		t.Errorf("Expected the standard logger to write through sawmill: %s", written)
+	}
+	if !strings.Contains(written, `stdlog_test.go","line":`+strconv.Itoa(line+1)) {
+		t.Errorf("Expected the source of the log.Printf call: %s", written)
+	}
+	if previous.Len() != 0 {
+		t.Errorf("Expected no output on the previous writer: %s", previous.String())
+	}
+
+	restore()
+	log.Print("after restore")
+	if !strings.HasPrefix(previous.String(), "app: ") || strings.Contains(buf.String(), "after restore") {
+		t.Errorf("Expected restore to reinstate the previous output and prefix: %q", previous.String())
+	}
+}
+
+func TestStdLogPartialOptions(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf), WithLevel(LevelDebug)))
+
+	output, prefix, flags := log.Writer(), log.Prefix(), log.Flags()
+	defer func() {
+		log.SetOutput(output)
+		log.SetPrefix(prefix)
+		log.SetFlags(flags)
+	}()
+
+	restore := RedirectStdLog(logger, &StdLogOptions{})
+	log.Print("plain line")
+	log.Print("[WARN] disk almost full")
+	restore()
+
+	NewStdLogger(logger, 0, StdLogRule{Prefix: "audit:"}).Print("audit: user deleted")
+
+	entries := decodeLines(t, buf)
+	want := []struct{ level, message string }{
+		{"INFO", "plain line"},
+		{"WARN", "disk almost full"},
+		{"INFO", "audit: user deleted"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %d records, got %s", len(want), buf.String())
+	}
+	for i, w := range want {
+		if entries[i]["level"] != w.level || entries[i]["message"] != w.message {
+			t.Errorf("Expected %s %q, got %v", w.level, w.message, entries[i])
+		}
+	}
+}
+
+func TestNewStdLoggerSource(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf), WithSourceInfo(true)))
+	std := NewStdLogger(logger, LevelInfo)
+
+	_, _, line, _ := runtime.Caller(0)
+	std.Println("with source")
+
+	if !strings.Contains(buf.String(), `stdlog_test.go","line":`+strconv.Itoa(line+1)) {
+		t.Errorf("Expected the source of the Println call: %s", buf.String())
+	}
+}
@@ -0,0 +1,91 @@
+package sawmill
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// TimerOptions configures the records written by Logger.Start. Zero levels
+// use the levels of DefaultTimerOptions.
+type TimerOptions struct {
+	Level         Level         // Level of completion records without an error
+	ErrorLevel    Level         // Level of completion records with an error
+	SlowThreshold time.Duration // Operations taking at least this long are slow; zero disables
+	SlowLevel     Level         // Level of slow completion records without an error
+	LogStart      bool          // Also write a record when the operation starts
+	StartLevel    Level         // Level of start records
+}
+
+// DefaultTimerOptions returns options that write completion records at Info,
+// or at Error when the operation failed
+func DefaultTimerOptions() *TimerOptions {
+	return &TimerOptions{
+		Level:      LevelInfo,
+		ErrorLevel: LevelError,
+		SlowLevel:  LevelWarn,
+		StartLevel: LevelDebug,
+	}
+}
+
+// Start begins timing an operation and returns a function that writes its
+// completion record with the operation name, duration and error. Start and
+// completion records share an OutputID. The returned function writes at most
+// one record.
+//
+// Example usage:
+//
+//	done := logger.Start("db.query", "table", "users")
+//	rows, err := db.Query(query)
+//	done(err)
+func (l *logger) Start(operation string, args ...interface{}) func(err error) {
+	opts := l.timerOptions()
+
+	id := generateOutputID()
+	start := time.Now()
+	if opts.LogStart {
+		l.logCorrelated(opts.StartLevel, operation+" started", id, append(args[:len(args):len(args)], "operation", operation), nil)
+	}
+
+	var finished atomic.Bool
+	return func(err error) {
+		if finished.Swap(true) {
+			return
+		}
+
+		elapsed := time.Since(start)
+		completion := append(args[:len(args):len(args)], "operation", operation, Duration("duration", elapsed))
+
+		level, msg := opts.Level, operation+" completed"
+		switch {
+		case err != nil:
+			level, msg = opts.ErrorLevel, operation+" failed"
+			completion = append(completion, Err(err))
+		case opts.SlowThreshold > 0 && elapsed >= opts.SlowThreshold:
+			level = opts.SlowLevel
+			completion = append(completion, Bool("slow", true))
+		}
+		l.logCorrelated(level, msg, id, completion, nil)
+	}
+}
+
+// WithTimerOptions returns a logger whose Start uses opts; nil restores the defaults
+func (l *logger) WithTimerOptions(opts *TimerOptions) Logger {
+	newLogger := l.clone()
+	newLogger.timer = opts
+	return newLogger
+}
+
+// timerOptions returns the logger's timer options with defaults for unset levels
+func (l *logger) timerOptions() *TimerOptions {
+	defaults := DefaultTimerOptions()
+	if l.timer == nil {
+		return defaults
+	}
+
+	opts := *l.timer
+	opts.Level = levelOr(opts.Level, defaults.Level)
+	opts.ErrorLevel = levelOr(opts.ErrorLevel, defaults.ErrorLevel)
+	opts.SlowLevel = levelOr(opts.SlowLevel, defaults.SlowLevel)
+	opts.StartLevel = levelOr(opts.StartLevel, defaults.StartLevel)
+	return &opts
+}
@@ -0,0 +1,135 @@
+package sawmill
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid JSON %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestStartCompletion(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf)))
+
+	done := logger.Start("db.query", "table", "users")
+	done(nil)
+	done(errors.New("ignored"))
+
+	entries := decodeLines(t, buf)
+	if len(entries) != 1 {
+		t.Fatalf("Expected a single completion record, got %d: %s", len(entries), buf.String())
+	}
+	entry := entries[0]
+	if entry["message"] != "db.query completed" || entry["level"] != "INFO" {
+		t.Errorf("Unexpected completion record: %v", entry)
+	}
+	attrs := entry["attributes"].(map[string]interface{})
+	if attrs["table"] != "users" || attrs["operation"] != "db.query" {
+		t.Errorf("Expected the start arguments and operation: %v", attrs)
+	}
+	if _, ok := attrs["duration"].(float64); !ok {
+		t.Errorf("Expected a duration: %v", attrs)
+	}
+	if entry["output_id"] == nil {
+		t.Errorf("Expected an OutputID: %v", entry)
+	}
+}
+
+func TestStartError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf)))
+
+	logger.Start("db.query")(errors.New("connection reset"))
+
+	entry := decodeLines(t, buf)[0]
+	if entry["message"] != "db.query failed" || entry["level"] != "ERROR" {
+		t.Errorf("Expected a failed record at Error: %v", entry)
+	}
+	if attrs := entry["attributes"].(map[string]interface{}); attrs["error.message"] != "connection reset" {
+		t.Errorf("Expected the expanded error: %v", attrs)
+	}
+}
+
+func TestStartSlowAndStartRecord(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf), WithLevel(LevelDebug))).WithTimerOptions(&TimerOptions{
+		Level:         LevelInfo,
+		ErrorLevel:    LevelError,
+		SlowThreshold: time.Millisecond,
+		SlowLevel:     LevelWarn,
+		LogStart:      true,
+		StartLevel:    LevelDebug,
+	})
+
+	done := logger.Start("import")
+	time.Sleep(2 * time.Millisecond)
+	done(nil)
+
+	entries := decodeLines(t, buf)
+	if len(entries) != 2 {
+		t.Fatalf("Expected start and completion records, got %s", buf.String())
+	}
+	if entries[0]["message"] != "import started" || entries[0]["level"] != "DEBUG" {
+		t.Errorf("Unexpected start record: %v", entries[0])
+	}
+	if entries[1]["level"] != "WARN" || entries[1]["attributes"].(map[string]interface{})["slow"] != true {
+		t.Errorf("Expected a slow completion at Warn: %v", entries[1])
+	}
+	if entries[0]["output_id"] == nil || entries[0]["output_id"] != entries[1]["output_id"] {
+		t.Errorf("Expected start and completion to share an OutputID: %v / %v", entries[0]["output_id"], entries[1]["output_id"])
+	}
+}
+
+func TestStartSource(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf), WithSourceInfo(true)))
+
+	done := logger.Start("op")
+	_, _, line, _ := runtime.Caller(0)
+	done(nil)
+
+	if !strings.Contains(buf.String(), `timer_test.go","line":`+strconv.Itoa(line+1)) {
+		t.Errorf("Expected the source of the done call: %s", buf.String())
+	}
+}
+
+func TestStartPartialOptions(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf), WithLevel(LevelDebug))).WithTimerOptions(&TimerOptions{
+		SlowThreshold: time.Nanosecond,
+		LogStart:      true,
+	})
+
+	done := logger.Start("import")
+	time.Sleep(time.Millisecond)
+	done(nil)
+	logger.Start("export")(errors.New("disk full"))
+
+	entries := decodeLines(t, buf)
+	want := []string{"DEBUG", "WARN", "DEBUG", "ERROR"}
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %d records, got %s", len(want), buf.String())
+	}
+	for i, level := range want {
+		if entries[i]["level"] != level {
+			t.Errorf("Expected record %d at %s, got %v", i, level, entries[i])
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package sawmill
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Kind identifies the type held by a Value
+type Kind int
+
+const (
+	KindAny Kind = iota
+	KindString
+	KindInt64
+	KindUint64
+	KindFloat64
+	KindBool
+	KindDuration
+	KindTime
+	KindError
+)
+
+// String returns the name of the kind
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "String"
+	case KindInt64:
+		return "Int64"
+	case KindUint64:
+		return "Uint64"
+	case KindFloat64:
+		return "Float64"
+	case KindBool:
+		return "Bool"
+	case KindDuration:
+		return "Duration"
+	case KindTime:
+		return "Time"
+	case KindError:
+		return "Error"
+	default:
+		return "Any"
+	}
+}
+
+// Value is a typed attribute value. Common types are stored without boxing so
+// formatters can encode them without reflection or allocation.
+type Value struct {
+	kind Kind
+	num  uint64      // Int64, Uint64, Float64 bits, Bool, Duration and Time nanoseconds
+	str  string      // String
+	any  interface{} // Any values, errors and the location of a Time
+}
+
+// Attr is a typed key/value pair built by String, Int64, Err and the other
+// constructors. Attrs can be passed to the logging methods alongside
+// key/value pairs and slog.Attr values, or to LogAttrs without boxing.
+type Attr struct {
+	Key   string
+	Value Value
+}
+
+// StringValue returns a Value for a string
+func StringValue(value string) Value {
+	return Value{kind: KindString, str: value}
+}
+
+// Int64Value returns a Value for an int64
+func Int64Value(value int64) Value {
+	return Value{kind: KindInt64, num: uint64(value)}
+}
+
+// Uint64Value returns a Value for a uint64
+func Uint64Value(value uint64) Value {
+	return Value{kind: KindUint64, num: value}
+}
+
+// Float64Value returns a Value for a float64
+func Float64Value(value float64) Value {
+	return Value{kind: KindFloat64, num: math.Float64bits(value)}
+}
+
+// BoolValue returns a Value for a bool
+func BoolValue(value bool) Value {
+	v := Value{kind: KindBool}
+	if value {
+		v.num = 1
+	}
+	return v
+}
+
+// DurationValue returns a Value for a time.Duration
+func DurationValue(value time.Duration) Value {
+	return Value{kind: KindDuration, num: uint64(value.Nanoseconds())}
+}
+
+// TimeValue returns a Value for a time.Time; times outside the range of
+// UnixNano are kept as an Any value
+func TimeValue(value time.Time) Value {
+	if value.IsZero() || value.Year() < 1678 || value.Year() > 2261 {
+		return Value{kind: KindAny, any: value}
+	}
+	return Value{kind: KindTime, num: uint64(value.UnixNano()), any: value.Location()}
+}
+
+// ErrorValue returns a Value for an error; a nil error is an Any value holding nil
+func ErrorValue(err error) Value {
+	if err == nil {
+		return Value{kind: KindAny}
+	}
+	return Value{kind: KindError, any: err}
+}
+
+// AnyValue returns a typed Value for common types and an Any value otherwise
+func AnyValue(value interface{}) Value {
+	switch v := value.(type) {
+	case string:
+		return StringValue(v)
+	case int:
+		return Int64Value(int64(v))
+	case int64:
+		return Int64Value(v)
+	case int32:
+		return Int64Value(int64(v))
+	case uint64:
+		return Uint64Value(v)
+	case uint:
+		return Uint64Value(uint64(v))
+	case uint32:
+		return Uint64Value(uint64(v))
+	case float64:
+		return Float64Value(v)
+	case float32:
+		return Float64Value(float64(v))
+	case bool:
+		return BoolValue(v)
+	case time.Duration:
+		return DurationValue(v)
+	case time.Time:
+		return TimeValue(v)
+	case Value:
+		return v
+	default:
+		return Value{kind: KindAny, any: value}
+	}
+}
+
+// valueFromSlog converts a resolved, non-group slog.Value
+func valueFromSlog(value slog.Value) Value {
+	switch value.Kind() {
+	case slog.KindString:
+		return StringValue(value.String())
+	case slog.KindInt64:
+		return Int64Value(value.Int64())
+	case slog.KindUint64:
+		return Uint64Value(value.Uint64())
+	case slog.KindFloat64:
+		return Float64Value(value.Float64())
+	case slog.KindBool:
+		return BoolValue(value.Bool())
+	case slog.KindDuration:
+		return DurationValue(value.Duration())
+	case slog.KindTime:
+		return TimeValue(value.Time())
+	default:
+		return Value{kind: KindAny, any: value.Any()}
+	}
+}
+
+// Kind returns the type held by the value
+func (v Value) Kind() Kind {
+	return v.kind
+}
+
+// Any returns the value as an interface{}, boxing typed values
+func (v Value) Any() interface{} {
+	switch v.kind {
+	case KindString:
+		return v.str
+	case KindInt64:
+		return int64(v.num)
+	case KindUint64:
+		return v.num
+	case KindFloat64:
+		return math.Float64frombits(v.num)
+	case KindBool:
+		return v.num == 1
+	case KindDuration:
+		return time.Duration(int64(v.num))
+	case KindTime:
+		return v.time()
+	default:
+		return v.any
+	}
+}
+
+// time rebuilds a KindTime value
+func (v Value) time() time.Time {
+	t := time.Unix(0, int64(v.num))
+	if loc, ok := v.any.(*time.Location); ok {
+		t = t.In(loc)
+	}
+	return t
+}
+
+// String formats the value like fmt's %v
+func (v Value) String() string {
+	switch v.kind {
+	case KindString:
+		return v.str
+	case KindInt64:
+		return strconv.FormatInt(int64(v.num), 10)
+	case KindUint64:
+		return strconv.FormatUint(v.num, 10)
+	case KindBool:
+		return strconv.FormatBool(v.num == 1)
+	case KindError:
+		return v.any.(error).Error()
+	default:
+		return fmt.Sprint(v.Any())
+	}
+}
+
+// String returns a string attribute
+func String(key, value string) Attr {
+	return Attr{Key: key, Value: StringValue(value)}
+}
+
+// Int returns an int attribute, stored as an int64
+func Int(key string, value int) Attr {
+	return Attr{Key: key, Value: Int64Value(int64(value))}
+}
+
+// Int64 returns an int64 attribute
+func Int64(key string, value int64) Attr {
+	return Attr{Key: key, Value: Int64Value(value)}
+}
+
+// Uint64 returns a uint64 attribute
+func Uint64(key string, value uint64) Attr {
+	return Attr{Key: key, Value: Uint64Value(value)}
+}
+
+// Float64 returns a float64 attribute
+func Float64(key string, value float64) Attr {
+	return Attr{Key: key, Value: Float64Value(value)}
+}
+
+// Bool returns a bool attribute
+func Bool(key string, value bool) Attr {
+	return Attr{Key: key, Value: BoolValue(value)}
+}
+
+// Duration returns a time.Duration attribute
+func Duration(key string, value time.Duration) Attr {
+	return Attr{Key: key, Value: DurationValue(value)}
+}
+
+// Time returns a time.Time attribute
+func Time(key string, value time.Time) Attr {
+	return Attr{Key: key, Value: TimeValue(value)}
+}
+
+// Err returns an attribute with the key "error" holding err
+func Err(err error) Attr {
+	return Attr{Key: "error", Value: ErrorValue(err)}
+}
+
+// Any returns an attribute for any value, typed when the value has a common type
+func Any(key string, value interface{}) Attr {
+	return Attr{Key: key, Value: AnyValue(value)}
+}
+
+// String formats the attribute as key=value
+func (a Attr) String() string {
+	return a.Key + "=" + a.Value.String()
+}
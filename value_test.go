@@ -0,0 +1,215 @@
+package sawmill
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTypedConstructors(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 30, 0, 500, time.UTC)
+	err := errors.New("boom")
+
+	tests := []struct {
+		attr Attr
+		kind Kind
+		want interface{}
+	}{
+		{String("s", "text"), KindString, "text"},
+		{Int("i", 42), KindInt64, int64(42)},
+		{Int64("i64", -7), KindInt64, int64(-7)},
+		{Uint64("u", math.MaxUint64), KindUint64, uint64(math.MaxUint64)},
+		{Float64("f", 1.5), KindFloat64, 1.5},
+		{Bool("b", true), KindBool, true},
+		{Duration("d", 2*time.Second), KindDuration, 2 * time.Second},
+		{Time("t", start), KindTime, start},
+		{Err(err), KindError, err},
+		{Any("a", []int{1}), KindAny, nil},
+	}
+
+	for _, tt := range tests {
+		if tt.attr.Value.Kind() != tt.kind {
+			t.Errorf("%s: expected kind %s, got %s", tt.attr.Key, tt.kind, tt.attr.Value.Kind())
+		}
+		if tt.want != nil && tt.attr.Value.Any() != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.attr.Key, tt.want, tt.attr.Value.Any())
+		}
+	}
+
+	if Err(nil).Value.Kind() != KindAny {
+		t.Error("Expected a nil error to be an Any value")
+	}
+	if got := Time("t", start).Value.Any().(time.Time); !got.Equal(start) || got.Location() != time.UTC {
+		t.Errorf("Expected the time and location to round-trip, got %v", got)
+	}
+}
+
+func TestAppendJSONMatchesEncodingJSON(t *testing.T) {
+	values := []interface{}{
+		"plain",
+		"quote \" backslash \\ newline \n tab \t",
+		"<html> &     \x01",
+		"invalid \xff utf8",
+		"héllo 世界",
+		0, -12, int8(-3), int16(300), int32(7), int64(math.MinInt64),
+		uint(1), uint8(2), uint16(3), uint32(4), uint64(math.MaxUint64),
+		0.0, 1.5, -2.25, 1e-7, 1e21, 123456789.125, float32(0.1), float32(1e-7),
+		true, false, nil,
+		time.Duration(1500),
+		map[string]int{"a": 1},
+		[]string{"x", "y"},
+	}
+
+	for _, value := range values {
+		want, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("json.Marshal(%#v): %v", value, err)
+		}
+		got, err := appendJSONAny(nil, value)
+		if err != nil {
+			t.Fatalf("appendJSONAny(%#v): %v", value, err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("appendJSONAny(%#v) = %s, expected %s", value, got, want)
+		}
+	}
+
+	if _, err := appendJSONAny(nil, math.NaN()); err == nil {
+		t.Error("Expected an error for NaN")
+	}
+	if _, err := appendJSONValue(nil, Float64Value(math.Inf(1))); err == nil {
+		t.Error("Expected an error for +Inf")
+	}
+}
+
+func TestAppendJSONValueTime(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 30, 0, 500, time.FixedZone("X", 3600))
+
+	want, _ := json.Marshal(start)
+	got, err := appendJSONValue(nil, TimeValue(start))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestTypedAttributesJSONOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf)))
+
+	logger.Info("Typed",
+		String("method", "GET"),
+		Int64("bytes", 512),
+		Duration("elapsed", 3*time.Millisecond),
+		"cache", "hit",
+		slog.Bool("retry", false),
+		Err(errors.New("boom")),
+	)
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", buf.String(), err)
+	}
+	attrs := entry["attributes"].(map[string]interface{})
+
+	expected := map[string]interface{}{
+		"method":  "GET",
+		"bytes":   float64(512),
+		"elapsed": float64(3 * time.Millisecond),
+		"cache":   "hit",
+		"retry":   false,
+	}
+	for key, want := range expected {
+		if attrs[key] != want {
+			t.Errorf("Expected %s=%v, got %v", key, want, attrs[key])
+		}
+	}
+	if !strings.Contains(buf.String(), `{"bytes":512,"cache":"hit","elapsed":3000000,"error.message":"boom","error.type":"*errors.errorString","method":"GET","retry":false}`) {
+		t.Errorf("Expected sorted attributes: %s", buf.String())
+	}
+}
+
+func TestTypedAttributesWithGroups(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf))).WithGroup("http")
+
+	logger.Info("Grouped", Int("status", 200), "path", "/", slog.String("method", "GET"))
+
+	output := buf.String()
+	for _, want := range []string{`"http.status":200`, `"http.path":"/"`, `"http.method":"GET"`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %s in output: %s", want, output)
+		}
+	}
+}
+
+func TestLogAttrs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf), WithLevel(LevelInfo)))
+
+	logger.LogAttrs(context.Background(), LevelDebug, "Skipped", String("k", "v"))
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output below the level, got %s", buf.String())
+	}
+
+	logger.LogAttrs(context.Background(), LevelWarn, "Written", Bool("ok", true), Float64("ratio", 0.5))
+	output := buf.String()
+	if !strings.Contains(output, `"ok":true`) || !strings.Contains(output, `"ratio":0.5`) {
+		t.Errorf("Expected typed attributes in output: %s", output)
+	}
+}
+
+func TestTypedValueOverridesUntypedKey(t *testing.T) {
+	attrs := NewFlatAttributes()
+	attrs.SetByDotNotation("key", "untyped")
+	attrs.SetValue("key", Int64Value(1))
+	attrs.SetValue("key", Int64Value(2))
+
+	if attrs.Size() != 1 {
+		t.Errorf("Expected one attribute, got %d", attrs.Size())
+	}
+	if value, _ := attrs.GetByDotNotation("key"); value != int64(2) {
+		t.Errorf("Expected the latest typed value, got %v", value)
+	}
+
+	attrs.SetByDotNotation("key", "again")
+	data, err := attrs.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"key":"again"}` {
+		t.Errorf("Expected the untyped value to replace the typed one, got %s", data)
+	}
+
+	if !attrs.DeleteByDotNotation("key") || !attrs.IsEmpty() {
+		t.Error("Expected the key to be deleted")
+	}
+}
+
+func TestAppendJSONTypedDoesNotAllocate(t *testing.T) {
+	attrs := NewFlatAttributes()
+	attrs.SetValue("method", StringValue("GET"))
+	attrs.SetValue("bytes", Int64Value(512))
+	attrs.SetValue("elapsed", DurationValue(time.Millisecond))
+	attrs.SetValue("ok", BoolValue(true))
+
+	dst := make([]byte, 0, 256)
+	attrs.AppendJSON(dst)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := attrs.AppendJSON(dst[:0]); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs > 0 {
+		t.Errorf("Expected no allocations encoding typed attributes, got %v", allocs)
+	}
+}
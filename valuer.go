@@ -0,0 +1,85 @@
+package sawmill
+
+import (
+	"log/slog"
+)
+
+// LogValuer is implemented by values that compute their logged form when a
+// record is written rather than when it is logged. It is the same interface as
+// slog.LogValuer, so existing slog valuers work unchanged. Valuers are
+// resolved after the level check, callbacks and filters, and a valuer that
+// returns another valuer is resolved again.
+type LogValuer = slog.LogValuer
+
+// Lazy returns a LogValuer that calls fn only if the record is written
+//
+// Example usage:
+//
+//	logger.Debug("Cache state", "entries", sawmill.Lazy(func() any { return cache.Dump() }))
+func Lazy(fn func() any) LogValuer {
+	return lazyValue(fn)
+}
+
+// lazyValue adapts a function to LogValuer
+type lazyValue func() any
+
+func (f lazyValue) LogValue() slog.Value {
+	return slog.AnyValue(f())
+}
+
+// ResolveValuers replaces LogValuer attributes with their resolved values.
+// Built-in handlers call it before formatting; custom handlers that read
+// attribute values directly should call it too.
+func (r *Record) ResolveValuers() {
+	if r.Attributes != nil {
+		r.Attributes.resolveValuers()
+	}
+}
+
+// isLogValuer reports whether a value is resolved lazily
+func isLogValuer(value interface{}) bool {
+	_, ok := value.(LogValuer)
+	return ok
+}
+
+// resolveValuers resolves every LogValuer value in place, expanding struct and group results
+func (f *FlatAttributes) resolveValuers() {
+	f.mu.Lock()
+	if !f.hasValuers {
+		f.mu.Unlock()
+		return
+	}
+	f.hasValuers = false
+
+	pending := make(map[string]LogValuer)
+	for i := 0; i < f.smallCount; i++ {
+		if valuer, ok := f.smallData[i].value.(LogValuer); ok {
+			pending[f.smallData[i].key] = valuer
+		}
+	}
+	for key, value := range f.data {
+		if valuer, ok := value.(LogValuer); ok {
+			pending[key] = valuer
+		}
+	}
+	f.mu.Unlock()
+
+	for key, valuer := range pending {
+		f.DeleteByDotNotation(key)
+
+		// Resolve follows chains of valuers and recovers from panics in LogValue
+		value := slog.AnyValue(valuer).Resolve()
+		err, isError := asError(value.Any())
+		switch {
+		case isError:
+			// Errors are expanded before structs, as for logging arguments
+			f.SetError(key, err)
+		case value.Kind() == slog.KindGroup:
+			f.SetAttr(nil, slog.Attr{Key: key, Value: value})
+		case shouldExpandStruct(value.Any()):
+			f.ExpandStruct(key, value.Any())
+		default:
+			f.SetByDotNotation(key, value.Any())
+		}
+	}
+}
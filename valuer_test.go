@@ -0,0 +1,118 @@
+package sawmill
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// testUser implements slog.LogValuer and hides its password
+type testUser struct {
+	ID       int
+	Password string
+}
+
+func (u testUser) LogValue() slog.Value {
+	return slog.GroupValue(slog.Int("id", u.ID))
+}
+
+// chainedValuer returns another valuer until depth reaches zero
+type chainedValuer struct{ depth int }
+
+func (v chainedValuer) LogValue() slog.Value {
+	if v.depth == 0 {
+		return slog.StringValue("resolved")
+	}
+	return slog.AnyValue(chainedValuer{depth: v.depth - 1})
+}
+
+func TestLazyNotEvaluatedWhenDisabled(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf), WithLevel(LevelInfo)))
+
+	calls := 0
+	payload := Lazy(func() any {
+		calls++
+		return "expensive"
+	})
+
+	logger.Debug("Skipped", "payload", payload)
+	if calls != 0 {
+		t.Errorf("Expected a disabled record not to evaluate Lazy, got %d calls", calls)
+	}
+
+	logger.Info("Written", "payload", payload)
+	if calls != 1 {
+		t.Errorf("Expected Lazy to be evaluated once, got %d calls", calls)
+	}
+	if !strings.Contains(buf.String(), `"payload":"expensive"`) {
+		t.Errorf("Expected the resolved value in output: %s", buf.String())
+	}
+}
+
+func TestLazyResolvedAfterCallbacksAndFilters(t *testing.T) {
+	buf := &bytes.Buffer{}
+	filter := MustCompileFilter(`!has(skip)`)
+	logger := New(NewJSONHandler(WithWriter(buf), WithFilter(filter)))
+
+	calls := 0
+	payload := Lazy(func() any {
+		calls++
+		return "expensive"
+	})
+
+	var seenInCallback interface{}
+	logger = logger.WithCallback(func(record *Record) *Record {
+		seenInCallback, _ = record.Attributes.GetByDotNotation("payload")
+		return record
+	})
+
+	logger.Info("Filtered", "payload", payload, "skip", true)
+	if calls != 0 {
+		t.Errorf("Expected a filtered record not to evaluate Lazy, got %d calls", calls)
+	}
+	if _, ok := seenInCallback.(LogValuer); !ok {
+		t.Errorf("Expected callbacks to see the unresolved valuer, got %T", seenInCallback)
+	}
+
+	logger.Info("Kept", "payload", payload)
+	if calls != 1 || !strings.Contains(buf.String(), `"payload":"expensive"`) {
+		t.Errorf("Expected the kept record to be resolved: %s", buf.String())
+	}
+}
+
+func TestLogValuerResolution(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf), WithAttributeFormat("flat")))
+
+	logger.Info("Login",
+		"user", testUser{ID: 7, Password: "secret"},
+		"chain", chainedValuer{depth: 3},
+		"config", Lazy(func() any { return struct{ Port int }{Port: 8080} }),
+	)
+
+	out := buf.String()
+	if strings.Contains(out, "secret") || !strings.Contains(out, `"user.id":7`) {
+		t.Errorf("Expected the valuer's group instead of the struct: %s", out)
+	}
+	if !strings.Contains(out, `"chain":"resolved"`) {
+		t.Errorf("Expected chained valuers to be resolved: %s", out)
+	}
+	if !strings.Contains(out, `"config.port":8080`) {
+		t.Errorf("Expected a resolved struct to be expanded: %s", out)
+	}
+}
+
+func TestLazyResolvedToError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(NewJSONHandler(WithWriter(buf), WithAttributeFormat("flat")))
+
+	logger.Info("Lookup failed", "e", Lazy(func() any { return errors.New("boom") }))
+
+	out := buf.String()
+	if !strings.Contains(out, `"e.message":"boom"`) || !strings.Contains(out, `"e.type":"*errors.errorString"`) {
+		t.Errorf("Expected the resolved error to be expanded: %s", out)
+	}
+}